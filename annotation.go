@@ -0,0 +1,91 @@
+/**
+ * Annotations are metadata of sections and items, written as comments
+ * starting with '#@' right above them. They don't change the values, but
+ * tools built on config files can read them, e.g. to alert the owners.
+ *
+ *      e.g. config file:
+ *          > #@owner: platform-team
+ *          > #@deprecated
+ *          > [db]
+ *          > #@unit: ms
+ *          > timeout: 300
+ *
+ *      conf.SectionAnnotations("db") is {"owner": "platform-team",
+ *      "deprecated": ""}, and the item 'timeout' has {"unit": "ms"}.
+ *
+ *  Only annotation lines right above a section or an item belong to it, so
+ *  an empty line or another comment line in between drops them.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 22:52:19
+ */
+
+package goconf
+
+import (
+	"strings"
+)
+
+const _ANNOTATION_TAG = '@'
+
+// Annotations returns a copy of the annotations of the item, nil if it has
+// none.
+func (item *Item) Annotations() map[string]string {
+	return cloneAnnotations(item.annotations)
+}
+
+// SectionAnnotations returns a copy of the annotations of section 'name',
+// nil if it has none.
+func (conf *Conf) SectionAnnotations(name string) map[string]string {
+	return cloneAnnotations(conf.annotations[name])
+}
+
+func isAnnotation(line string) bool {
+	return len(line) > 1 && line[0] == _COMMENT_TAG && line[1] == _ANNOTATION_TAG
+}
+
+// addAnnotation adds annotation line '#@key: value' to annotations. An
+// annotation without value has an empty one.
+func addAnnotation(annotations map[string]string, line string) map[string]string {
+	line = line[2:]
+	key, val := line, ""
+	if sep := strings.IndexByte(line, _KV_SEP); sep >= 0 {
+		key, val = line[:sep], line[sep+1:]
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[strings.Trim(key, _SPACE_CHARS)] = strings.Trim(val, _SPACE_CHARS)
+	return annotations
+}
+
+// annotateSection adds annotations to section 'name'. The annotations of a
+// reopened section are merged.
+func (conf *Conf) annotateSection(name string, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	if conf.annotations == nil {
+		conf.annotations = make(map[string]map[string]string)
+	}
+	if conf.annotations[name] == nil {
+		conf.annotations[name] = annotations
+		return
+	}
+	for key, val := range annotations {
+		conf.annotations[name][key] = val
+	}
+}
+
+func cloneAnnotations(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+
+	clone := make(map[string]string, len(annotations))
+	for key, val := range annotations {
+		clone[strings.Clone(key)] = strings.Clone(val)
+	}
+	return clone
+}
@@ -0,0 +1,75 @@
+/**
+ * Arena of items, used by WithItemArena. Items are allocated from chunks,
+ * and chunks are pooled, so reloading a massive config reuses the memory of
+ * the last one after Conf.Release.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 21:02:44
+ */
+
+package goconf
+
+import (
+	"sync"
+)
+
+const _ARENA_CHUNK = 4096 // items per chunk
+
+var chunkPool = sync.Pool{
+	New: func() interface{} {
+		chunk := make([]Item, _ARENA_CHUNK)
+		return &chunk
+	},
+}
+
+type itemArena struct {
+	chunks []*[]Item
+	used   int // items allocated from the last chunk
+}
+
+func (a *itemArena) alloc() *Item {
+	if len(a.chunks) == 0 || a.used == _ARENA_CHUNK {
+		a.chunks = append(a.chunks, chunkPool.Get().(*[]Item))
+		a.used = 0
+	}
+
+	item := &(*a.chunks[len(a.chunks)-1])[a.used]
+	a.used++
+	return item
+}
+
+// adopt takes the chunks of b, whose items are allocated from b onwards.
+func (a *itemArena) adopt(b *itemArena) {
+	if len(b.chunks) == 0 {
+		return
+	}
+	a.chunks = append(a.chunks, b.chunks...)
+	a.used = b.used
+}
+
+// release returns all the chunks to the pool. Items are cleared, so the
+// strings they refer to can be collected.
+func (a *itemArena) release() {
+	for _, chunk := range a.chunks {
+		items := *chunk
+		for idx := range items {
+			items[idx] = Item{}
+		}
+		chunkPool.Put(chunk)
+	}
+	a.chunks = nil
+	a.used = 0
+}
+
+// newItem allocates an item from the arena of conf, if any.
+func (conf *Conf) newItem(key, val string) *Item {
+	if conf.arena == nil {
+		return &Item{key: key, val: val, sep: conf.opts.elementSep}
+	}
+
+	item := conf.arena.alloc()
+	item.key = key
+	item.val = val
+	item.sep = conf.opts.elementSep
+	return item
+}
@@ -0,0 +1,34 @@
+/**
+ * Conversions cached by items, so a hot item read repeatedly, e.g. by
+ * GetIntArray in a loop, isn't split and parsed every time.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 14:20:45
+ */
+
+package goconf
+
+// A conversion is the value of an item converted to a type. It's stale if
+// the value of the item is changed, e.g. by an env variable.
+type conversion struct {
+	typ string // e.g. "int array"
+	sep string // separator of elements, for arrays
+	val string // value converted
+	v   interface{}
+}
+
+// cached returns the value of the item converted to typ by convert, which
+// is only called if the cached conversion is of another type or stale.
+// Errors aren't cached.
+func (item *Item) cached(typ, sep string, convert func() (interface{}, error)) (interface{}, error) {
+	if c := item.conv.Load(); c != nil && c.typ == typ && c.sep == sep && c.val == item.val {
+		return c.v, nil
+	}
+
+	v, err := convert()
+	if err != nil {
+		return nil, err
+	}
+	item.conv.Store(&conversion{typ: typ, sep: sep, val: item.val, v: v})
+	return v, nil
+}
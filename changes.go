@@ -0,0 +1,127 @@
+/**
+ * Change events of reloads, for integrations pushing config changes into
+ * their own event systems.
+ *
+ *      e.g.
+ *          events := conf.Changes()
+ *          go func() {
+ *              for e := range events {
+ *                  bus.Publish("config", e.Section, e.Key, e.Old, e.New)
+ *              }
+ *          }()
+ *          err := conf.Reload()
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 15:41:26
+ */
+
+package goconf
+
+const _CHANGES_BUFFER = 64
+
+// A ChangeEvent is a change of an item by Reload. Old is empty for an added
+// item, and New is empty for a removed one. Values of secrets, see IsSecret,
+// are redacted.
+type ChangeEvent struct {
+	Section string // empty for the global section
+	Key     string
+	Old     string
+	New     string
+}
+
+// Changes returns a channel receiving an event for every item changed by
+// a successful Reload. Events are sent without blocking Reload, so a
+// receiver which doesn't keep up with 64 pending events misses the later
+// ones. The channel is never closed.
+func (conf *Conf) Changes() <-chan ChangeEvent {
+	ch := make(chan ChangeEvent, _CHANGES_BUFFER)
+
+	conf.changesMu.Lock()
+	conf.changes = append(conf.changes, ch)
+	conf.changesMu.Unlock()
+
+	return ch
+}
+
+// Reload parses the config file again with the options of conf. If it
+// succeeds, the items of conf are replaced at once, the current section is
+// kept by name and the defaults are kept. Otherwise conf is unchanged.
+//
+// With WithMmap or WithItemArena, the replaced items stay in memory until
+// Release, as the items and strings got before may still be used. So a
+// conf reloaded many times should be replaced by a new one and released.
+func (conf *Conf) Reload() error {
+	fresh := newConf(conf.filePath, conf.opts)
+	fresh.logger = conf.logger
+	if err := fresh.Parse(); err != nil {
+		conf.logf("failed to reload %s: %s", conf.filePath, err)
+		return err
+	}
+	fresh.defaults = conf.defaults
+
+	conf.changesMu.Lock()
+	var changes []Change
+	if len(conf.changes) != 0 || conf.logger != nil {
+		changes = Diff(conf, fresh)
+		redactChanges(conf, fresh, changes)
+	}
+	conf.changesMu.Unlock()
+
+	conf.logf("reloaded %s, changes: %d", conf.filePath, len(changes))
+	for i := range changes {
+		conf.logf("%s", changes[i].String())
+	}
+
+	curName := conf.sectionName(conf.cur)
+	conf.setItems(fresh)
+	conf.mappings = append(conf.mappings, fresh.mappings...)
+	if fresh.arena != nil {
+		conf.arena.adopt(fresh.arena)
+	}
+	if conf.cur = conf.sections[curName]; conf.cur == nil {
+		conf.cur = conf.sections[_GLOBAL]
+	}
+
+	conf.sendChanges(changes)
+	return nil
+}
+
+// redactChanges redacts the values of secrets in the changes from a to b,
+// as they're logged and sent to receivers of Changes.
+func redactChanges(a, b *Conf, changes []Change) {
+	redact := func(val string) string {
+		if val == "" {
+			return ""
+		}
+		return _REDACTED
+	}
+
+	for i := range changes {
+		c := &changes[i]
+		name := c.sectionName()
+		path := itemPath(name, c.Key)
+		for _, conf := range []*Conf{a, b} {
+			if item := conf.sections[name].get(c.Key); item != nil && IsSecret(path, item) {
+				c.Value, c.OldValue = redact(c.Value), redact(c.OldValue)
+			}
+		}
+	}
+}
+
+func (conf *Conf) sendChanges(changes []Change) {
+	conf.changesMu.Lock()
+	defer conf.changesMu.Unlock()
+
+	for _, c := range changes {
+		e := ChangeEvent{Section: c.Section, Key: c.Key, Old: c.OldValue}
+		if c.Op == OpSet {
+			e.New = c.Value
+		}
+		for _, ch := range conf.changes {
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+	}
+}
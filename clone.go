@@ -0,0 +1,89 @@
+/**
+ * Deep copy of a conf, so a component can change or re-section its own
+ * copy without affecting the others sharing the original.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 14:41:03
+ */
+
+package goconf
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Clone returns a deep copy of conf with the same current section. The
+// copy doesn't record accesses, and its items are allocated one by one
+// even if conf uses an item arena.
+func (conf *Conf) Clone() *Conf {
+	// Strings are immutable, so they're shared unless they refer to the
+	// mappings of conf, which the copy may outlive
+	str := func(s string) string { return s }
+	if len(conf.mappings) != 0 {
+		str = strings.Clone
+	}
+
+	opts := *conf.opts
+	clone := &Conf{
+		filePath:  conf.filePath,
+		eleSep:    conf.eleSep,
+		opts:      &opts,
+		itemCount: conf.itemCount,
+		sections:  cloneSections(conf.sections, str),
+		defaults:  cloneSections(conf.defaults, str),
+		logger:    conf.logger,
+		warnings:  slices.Clone(conf.warnings),
+		warned:    maps.Clone(conf.warned),
+	}
+	clone.cur = clone.sections[conf.sectionName(conf.cur)]
+	if clone.cur == nil {
+		clone.cur = clone.sections[_GLOBAL]
+	}
+
+	if conf.annotations != nil {
+		clone.annotations = make(map[string]map[string]string, len(conf.annotations))
+		for name, a := range conf.annotations {
+			clone.annotations[str(name)] = cloneAnnotations(a)
+		}
+	}
+	if conf.pending != nil {
+		clone.pending = make(map[string]*pendingSection, len(conf.pending))
+		for name, p := range conf.pending {
+			cp := *p
+			cp.body = str(p.body)
+			clone.pending[str(name)] = &cp
+		}
+	}
+
+	return clone
+}
+
+func cloneSections(sections map[string]*section, str func(string) string) map[string]*section {
+	if sections == nil {
+		return nil
+	}
+
+	clone := make(map[string]*section, len(sections))
+	for name, sec := range sections {
+		cs := newSection()
+		for _, key := range sec.keys {
+			cs.set(str(key), sec.m[key].clone(str))
+		}
+		clone[str(name)] = cs
+	}
+	return clone
+}
+
+// clone returns a copy of the item, whose strings are copied by str.
+func (item *Item) clone(str func(string) string) *Item {
+	return &Item{
+		key:         str(item.key),
+		val:         str(item.val),
+		raw:         str(item.raw),
+		annotations: cloneAnnotations(item.annotations),
+		origin:      item.origin,
+		sep:         item.sep,
+	}
+}
@@ -0,0 +1,256 @@
+/**
+ * goconf checks config files from the shell, e.g. in CI or pre-deploy
+ * hooks.
+ *
+ *      Usage:
+ *          goconf validate [--schema schema.json] file.conf...
+ *          goconf lint [--key-pattern regexp] [--forbid key=val,...]... [--require-annotation name]... file.conf...
+ *          goconf get file.conf section.key
+ *          goconf set file.conf section.key value
+ *
+ *      validate parses the files, and validates them by the schema of
+ *      goconf.ParseSchema if it's given. Errors are printed with their
+ *      lines, e.g. 'app.conf:12: need ':' in a line'.
+ *
+ *      lint runs the built-in rules of goconf.Linter given by the flags:
+ *      KeyPattern, ForbiddenValues and RequiredAnnotation. Diagnostics are
+ *      printed after their files, and only errors fail, not warnings.
+ *
+ *      get prints the value of an item, and set changes it by
+ *      goconf.PatchFile, which keeps comments and the order of lines. Keys
+ *      without '.' are global items.
+ *
+ *      The exit status is 0 on success, 1 if a file is invalid, breaks a
+ *      lint rule or an item is missing, and 2 for bad usage.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 12:40:18
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/chosen0ne/goconf"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	_EXIT_OK    = 0
+	_EXIT_FAIL  = 1
+	_EXIT_USAGE = 2
+)
+
+const _USAGE = `Usage:
+    goconf validate [--schema schema.json] file.conf...
+    goconf lint [--key-pattern regexp] [--forbid key=val,...]... [--require-annotation name]... file.conf...
+    goconf get file.conf section.key
+    goconf set file.conf section.key value
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command of args, and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, _USAGE)
+		return _EXIT_USAGE
+	}
+
+	switch args[0] {
+	case "validate":
+		return validate(args[1:], stdout, stderr)
+	case "lint":
+		return lint(args[1:], stdout, stderr)
+	case "get":
+		return get(args[1:], stdout, stderr)
+	case "set":
+		return set(args[1:], stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, _USAGE)
+		return _EXIT_OK
+	}
+
+	fmt.Fprintf(stderr, "goconf: unknown command '%s'\n%s", args[0], _USAGE)
+	return _EXIT_USAGE
+}
+
+// parseArgs parses the flags of fs, which may follow the positional
+// arguments, e.g. 'file.conf --schema s.json'. It returns the positional
+// arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func validate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	schemaFile := fs.String("schema", "", "JSON schema to validate the files by")
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return _EXIT_USAGE
+	} else if len(files) == 0 {
+		fmt.Fprint(stderr, _USAGE)
+		return _EXIT_USAGE
+	}
+
+	var schema *goconf.Schema
+	if *schemaFile != "" {
+		data, err := os.ReadFile(*schemaFile)
+		if err == nil {
+			schema, err = goconf.ParseSchema(data)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "goconf: bad schema %s: %s\n", *schemaFile, err)
+			return _EXIT_USAGE
+		}
+	}
+
+	status := _EXIT_OK
+	for _, file := range files {
+		conf := goconf.New(file)
+		if err := conf.Parse(); err != nil {
+			fmt.Fprintln(stdout, err)
+			status = _EXIT_FAIL
+			continue
+		}
+		if schema == nil {
+			continue
+		}
+		for _, err := range conf.Validate(schema) {
+			// errors of items are located already
+			if msg := err.Error(); strings.HasPrefix(msg, file+":") {
+				fmt.Fprintln(stdout, msg)
+			} else {
+				fmt.Fprintf(stdout, "%s: %s\n", file, msg)
+			}
+			status = _EXIT_FAIL
+		}
+	}
+
+	return status
+}
+
+// listFlag is a flag which may be given several times.
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *listFlag) Set(val string) error {
+	*l = append(*l, val)
+	return nil
+}
+
+func lint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keyPattern := fs.String("key-pattern", "", "regexp which keys and section names must match")
+	var forbids, annotations listFlag
+	fs.Var(&forbids, "forbid", "forbidden values of an item, e.g. 'db.password=changeme,123456'")
+	fs.Var(&annotations, "require-annotation", "annotation which every section must have")
+	files, err := parseArgs(fs, args)
+	if err != nil {
+		return _EXIT_USAGE
+	} else if len(files) == 0 {
+		fmt.Fprint(stderr, _USAGE)
+		return _EXIT_USAGE
+	}
+
+	linter := goconf.NewLinter()
+	if *keyPattern != "" {
+		re, err := regexp.Compile(*keyPattern)
+		if err != nil {
+			fmt.Fprintf(stderr, "goconf: bad key pattern: %s\n", err)
+			return _EXIT_USAGE
+		}
+		linter.Add(goconf.KeyPattern(re))
+	}
+	for _, forbid := range forbids {
+		key, vals, ok := strings.Cut(forbid, "=")
+		if !ok || key == "" {
+			fmt.Fprintf(stderr, "goconf: bad forbidden values '%s', need 'key=val,...'\n", forbid)
+			return _EXIT_USAGE
+		}
+		linter.Add(goconf.ForbiddenValues(key, strings.Split(vals, ",")...))
+	}
+	for _, name := range annotations {
+		linter.Add(goconf.RequiredAnnotation(name))
+	}
+
+	status := _EXIT_OK
+	for _, file := range files {
+		conf := goconf.New(file)
+		if err := conf.Parse(); err != nil {
+			fmt.Fprintln(stdout, err)
+			status = _EXIT_FAIL
+			continue
+		}
+		diags := linter.Run(conf)
+		for _, d := range diags {
+			fmt.Fprintf(stdout, "%s: %s\n", file, d)
+		}
+		if goconf.HasErrors(diags) {
+			status = _EXIT_FAIL
+		}
+	}
+
+	return status
+}
+
+func get(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 2 {
+		fmt.Fprint(stderr, _USAGE)
+		return _EXIT_USAGE
+	}
+
+	conf := goconf.New(args[0])
+	if err := conf.Parse(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return _EXIT_FAIL
+	}
+	item, err := conf.GetItem(args[1])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return _EXIT_FAIL
+	}
+
+	fmt.Fprintln(stdout, item.ToString())
+	return _EXIT_OK
+}
+
+func set(args []string, stderr io.Writer) int {
+	if len(args) != 3 {
+		fmt.Fprint(stderr, _USAGE)
+		return _EXIT_USAGE
+	}
+
+	change := goconf.Change{Op: goconf.OpSet, Key: args[1], Value: args[2]}
+	if dot := strings.IndexByte(args[1], '.'); dot > 0 {
+		change.Section, change.Key = args[1][:dot], args[1][dot+1:]
+	}
+	if err := goconf.PatchFile(args[0], []goconf.Change{change}); err != nil {
+		fmt.Fprintln(stderr, err)
+		return _EXIT_FAIL
+	}
+
+	return _EXIT_OK
+}
@@ -0,0 +1,107 @@
+/**
+ * Unit test cases for the goconf command
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 12:58:40
+ */
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.conf")
+	bad := filepath.Join(dir, "bad.conf")
+	typed := filepath.Join(dir, "typed.conf")
+	schema := filepath.Join(dir, "schema.json")
+	os.WriteFile(good, []byte("port: 80\n"), 0644)
+	os.WriteFile(bad, []byte("port: 80\nno separator\n"), 0644)
+	os.WriteFile(typed, []byte("name: app\nport: http\n"), 0644)
+	os.WriteFile(schema, []byte(`{"items": {"port": {"type": "uint", "required": true}}}`), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"validate", good}, &stdout, &stderr); status != 0 || stdout.Len() != 0 {
+		t.Errorf("need a valid file, status: %d, out: %s", status, stdout.String())
+	}
+
+	if status := run([]string{"validate", good, bad}, &stdout, &stderr); status != 1 ||
+		!strings.HasPrefix(stdout.String(), bad+":2:") {
+		t.Errorf("need a located syntax error, status: %d, out: %s", status, stdout.String())
+	}
+
+	stdout.Reset()
+	if status := run([]string{"validate", typed, "--schema", schema}, &stdout, &stderr); status != 1 ||
+		!strings.HasPrefix(stdout.String(), typed+":2:") {
+		t.Errorf("need a located type error, status: %d, out: %s", status, stdout.String())
+	}
+
+	for _, args := range [][]string{nil, {"validate"}, {"nocmd"}, {"validate", "--schema", bad, good}} {
+		if status := run(args, &stdout, &stderr); status != 2 {
+			t.Errorf("need a usage error of %v, status: %d", args, status)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("Name: app\n#@owner: infra\n[db]\npassword: changeme\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"lint", path, "--key-pattern", "^[a-z_]+$", "--require-annotation", "owner"}
+	if status := run(args, &stdout, &stderr); status != 0 ||
+		stdout.String() != path+": warning: Name: key doesn't match '^[a-z_]+$' [key-pattern]\n" {
+		t.Errorf("warnings shouldn't fail, status: %d, out: %s", status, stdout.String())
+	}
+
+	stdout.Reset()
+	if status := run([]string{"lint", "--forbid", "db.password=changeme,123456", path}, &stdout, &stderr); status != 1 ||
+		stdout.String() != path+": error: db.password: value 'changeme' is forbidden [forbidden-value]\n" {
+		t.Errorf("need a forbidden value error, status: %d, out: %s", status, stdout.String())
+	}
+
+	for _, args := range [][]string{{"lint"}, {"lint", "--key-pattern", "(", path}, {"lint", "--forbid", "x", path}} {
+		if status := run(args, &stdout, &stderr); status != 2 {
+			t.Errorf("need a usage error of %v, status: %d", args, status)
+		}
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("# app\nname: app\n\n[db]\n# primary\nhost: localhost\n"), 0644)
+
+	var stdout, stderr bytes.Buffer
+	if status := run([]string{"get", path, "db.host"}, &stdout, &stderr); status != 0 || stdout.String() != "localhost\n" {
+		t.Errorf("need the value of db.host, status: %d, out: %s", status, stdout.String())
+	}
+	if status := run([]string{"get", path, "db.port"}, &stdout, &stderr); status != 1 {
+		t.Errorf("need a missing item, status: %d", status)
+	}
+
+	if status := run([]string{"set", path, "db.host", "db.internal"}, &stdout, &stderr); status != 0 {
+		t.Fatalf("failed to set db.host, status: %d, err: %s", status, stderr.String())
+	}
+	if status := run([]string{"set", path, "port", "80"}, &stdout, &stderr); status != 0 {
+		t.Fatalf("failed to set port, status: %d, err: %s", status, stderr.String())
+	}
+	content, _ := os.ReadFile(path)
+	if string(content) != "# app\nname: app\nport: 80\n\n[db]\n# primary\nhost: db.internal\n" {
+		t.Errorf("need the structure of the file to be kept, content:\n%s", content)
+	}
+
+	for _, args := range [][]string{{"get", path}, {"set", path, "a"}} {
+		if status := run(args, &stdout, &stderr); status != 2 {
+			t.Errorf("need a usage error of %v, status: %d", args, status)
+		}
+	}
+	if status := run([]string{"set", path, "a", " "}, &stdout, &stderr); status != 1 {
+		t.Errorf("need an error for an empty value, status: %d", status)
+	}
+}
@@ -0,0 +1,282 @@
+/**
+ * Package cobraconf binds a goconf config struct to the flags of a cobra
+ * command, so every option is defined once, by a struct field. Bind defines
+ * a flag per field, and Load fills the struct with the precedence
+ * flag > env > file.
+ *
+ *      e.g.
+ *          type Config struct {
+ *              Name   string
+ *              Server struct {
+ *                  Port        int
+ *                  ReadTimeout string
+ *              }
+ *          }
+ *
+ *          cfg := &Config{}
+ *          cmd := &cobra.Command{
+ *              Use: "app",
+ *              RunE: func(cmd *cobra.Command, args []string) error {
+ *                  err := cobraconf.Load(cmd, cfg, "app.conf", goconf.WithEnvOverrides("APP"))
+ *                  ...
+ *              },
+ *          }
+ *          cobraconf.Bind(cmd, cfg)
+ *
+ *      defines the flags '--name', '--server.port' and '--server.read-timeout'.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 00:31:26
+ */
+
+package cobraconf
+
+import (
+	"errors"
+	"fmt"
+	"github.com/chosen0ne/goconf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind defines a flag on cmd for every field of the config struct. A field
+// is named like the first config item it's searched by, e.g. 'read-timeout'
+// for ReadTimeout, and fields of nested structs by 'section.field'. The
+// defaults of the flags are the current values of the fields. Fields of
+// types without flags, e.g. maps of sections, are skipped.
+func Bind(cmd *cobra.Command, configObjPtr interface{}) error {
+	obj, err := structOf(configObjPtr)
+	if err != nil {
+		return err
+	}
+
+	return walk(obj, "", func(name string, field reflect.Value) error {
+		define(cmd.Flags(), name, field)
+		return nil
+	})
+}
+
+// Load loads the config object from configFile by goconf.Load, then sets
+// the fields whose flags are given on the command line. With the option
+// goconf.WithEnvOverrides, environment variables override the file, and
+// flags override both.
+func Load(cmd *cobra.Command, configObjPtr interface{}, configFile string, opts ...goconf.Option) error {
+	obj, err := structOf(configObjPtr)
+	if err != nil {
+		return err
+	}
+	if err := goconf.Load(configObjPtr, configFile, opts...); err != nil {
+		return err
+	}
+
+	fs := cmd.Flags()
+	return walk(obj, "", func(name string, field reflect.Value) error {
+		if !fs.Changed(name) {
+			return nil
+		}
+		return set(fs, name, field)
+	})
+}
+
+func structOf(configObjPtr interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(configObjPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, errors.New("configObj must be a pointer to struct")
+	}
+	return v.Elem(), nil
+}
+
+// walk calls fn for every settable field of obj, except nested structs,
+// whose fields are visited by path.
+func walk(obj reflect.Value, prefix string, fn func(name string, field reflect.Value) error) error {
+	t := obj.Type()
+	for i := 0; i < obj.NumField(); i++ {
+		field := obj.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		name := prefix + flagName(t.Field(i).Name)
+		if field.Kind() == reflect.Struct {
+			if err := walk(field, name+".", fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(name, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// flagName converts 'AExampleField' to 'a-example-field'.
+func flagName(field string) string {
+	var sb strings.Builder
+	for idx, c := range field {
+		if unicode.IsUpper(c) {
+			if idx != 0 {
+				sb.WriteByte('-')
+			}
+			c = unicode.ToLower(c)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// define defines the flag of a field. Durations and slices of numbers are
+// flags of their string forms, e.g. '30s' and '80,443', and fields of other
+// types, e.g. maps of sections, have no flags.
+func define(fs *pflag.FlagSet, name string, field reflect.Value) {
+	usage := fmt.Sprintf("config item '%s'", name)
+	switch kind := field.Kind(); {
+	case field.Type() == durationType:
+		fs.String(name, time.Duration(field.Int()).String(), usage)
+	case isInt(kind):
+		fs.Int64(name, field.Int(), usage)
+	case isUint(kind):
+		fs.Uint64(name, field.Uint(), usage)
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		fs.Float64(name, field.Float(), usage)
+	case kind == reflect.Bool:
+		fs.Bool(name, field.Bool(), usage)
+	case kind == reflect.String:
+		fs.String(name, field.String(), usage)
+	case kind == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		fs.StringSlice(name, field.Convert(reflect.TypeOf([]string(nil))).Interface().([]string), usage)
+	case kind == reflect.Slice && isNumber(field.Type().Elem().Kind()):
+		eles := make([]string, field.Len())
+		for idx := range eles {
+			eles[idx] = fmt.Sprint(field.Index(idx).Interface())
+		}
+		fs.StringSlice(name, eles, usage)
+	}
+}
+
+func set(fs *pflag.FlagSet, name string, field reflect.Value) error {
+	var (
+		val interface{}
+		err error
+	)
+	switch kind := field.Kind(); {
+	case field.Type() == durationType:
+		var s string
+		if s, err = fs.GetString(name); err != nil {
+			return err
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return &goconf.TypeError{Key: name, Val: s, Type: "duration", Err: err}
+		}
+		field.SetInt(int64(d))
+		return nil
+	case isInt(kind):
+		var v int64
+		if v, err = fs.GetInt64(name); err != nil {
+			return err
+		} else if field.OverflowInt(v) {
+			return &goconf.TypeError{Key: name, Val: fmt.Sprint(v), Type: field.Type().String()}
+		}
+		field.SetInt(v)
+		return nil
+	case isUint(kind):
+		var v uint64
+		if v, err = fs.GetUint64(name); err != nil {
+			return err
+		} else if field.OverflowUint(v) {
+			return &goconf.TypeError{Key: name, Val: fmt.Sprint(v), Type: field.Type().String()}
+		}
+		field.SetUint(v)
+		return nil
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		var v float64
+		if v, err = fs.GetFloat64(name); err != nil {
+			return err
+		} else if field.OverflowFloat(v) {
+			return &goconf.TypeError{Key: name, Val: fmt.Sprint(v), Type: field.Type().String()}
+		}
+		field.SetFloat(v)
+		return nil
+	case kind == reflect.Bool:
+		val, err = fs.GetBool(name)
+	case kind == reflect.String:
+		val, err = fs.GetString(name)
+	case kind == reflect.Slice:
+		var eles []string
+		if eles, err = fs.GetStringSlice(name); err != nil {
+			return err
+		}
+		return setSlice(name, eles, field)
+	}
+	if err != nil {
+		return err
+	}
+
+	field.Set(reflect.ValueOf(val).Convert(field.Type()))
+	return nil
+}
+
+// setSlice sets a slice field of strings or numbers by the elements of its
+// flag. Numbers are parsed by the size of the element type, e.g. 16 bits
+// of a []uint16, and durations like '30s'.
+func setSlice(name string, eles []string, field reflect.Value) error {
+	eleType := field.Type().Elem()
+	vals := reflect.MakeSlice(field.Type(), len(eles), len(eles))
+	for idx, ele := range eles {
+		v := vals.Index(idx)
+		var err error
+		switch kind := eleType.Kind(); {
+		case kind == reflect.String:
+			v.SetString(ele)
+		case eleType == durationType:
+			var d time.Duration
+			if d, err = time.ParseDuration(ele); err == nil {
+				v.SetInt(int64(d))
+			}
+		case isInt(kind):
+			var n int64
+			if n, err = strconv.ParseInt(ele, 10, eleType.Bits()); err == nil {
+				v.SetInt(n)
+			}
+		case isUint(kind):
+			var n uint64
+			if n, err = strconv.ParseUint(ele, 10, eleType.Bits()); err == nil {
+				v.SetUint(n)
+			}
+		default:
+			var f float64
+			if f, err = strconv.ParseFloat(ele, eleType.Bits()); err == nil {
+				v.SetFloat(f)
+			}
+		}
+		if err != nil {
+			return &goconf.TypeError{Key: name, Val: strings.Join(eles, ","), Type: field.Type().String(), Err: err}
+		}
+	}
+
+	field.Set(vals)
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k == reflect.Int || k == reflect.Int8 || k == reflect.Int16 ||
+		k == reflect.Int32 || k == reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k == reflect.Uint || k == reflect.Uint8 || k == reflect.Uint16 ||
+		k == reflect.Uint32 || k == reflect.Uint64
+}
+
+func isNumber(k reflect.Kind) bool {
+	return isInt(k) || isUint(k) || k == reflect.Float32 || k == reflect.Float64
+}
@@ -0,0 +1,112 @@
+/**
+ * Unit test cases for cobraconf
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 00:46:10
+ */
+
+package cobraconf
+
+import (
+	"errors"
+	"github.com/chosen0ne/goconf"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type server struct {
+	Port        int
+	ReadTimeout string
+	Hosts       []string
+}
+
+type config struct {
+	Name   string
+	Debug  bool
+	Ratio  float64
+	Server server
+}
+
+func TestBindAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	content := "name: app\ndebug: false\nratio: 0.5\n[server]\nport: 8080\nread-timeout: 3s\nhosts: a b\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_SERVER_PORT", "9090")
+	t.Setenv("APP_SERVER_READ_TIMEOUT", "5s")
+
+	cfg := &config{Ratio: 0.1}
+	cmd := &cobra.Command{Use: "app"}
+	if err := Bind(cmd, cfg); err != nil {
+		t.Fatalf("failed to bind, err: %s", err)
+	}
+	for _, name := range []string{"name", "debug", "ratio", "server.port", "server.read-timeout", "server.hosts"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("flag '%s' isn't defined", name)
+		}
+	}
+
+	if err := cmd.Flags().Parse([]string{"--server.read-timeout=10s", "--debug"}); err != nil {
+		t.Fatalf("failed to parse flags, err: %s", err)
+	}
+	if err := Load(cmd, cfg, path, goconf.WithEnvOverrides("APP")); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+
+	expected := &config{
+		Name:   "app",                                   // file
+		Debug:  true,                                    // flag
+		Ratio:  0.5,                                     // file
+		Server: server{9090, "10s", []string{"a", "b"}}, // env, flag over env, file
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("not expected config, output: %+v, expected: %+v", cfg, expected)
+	}
+}
+
+func TestBindTypes(t *testing.T) {
+	type typed struct {
+		Level    int8
+		Timeout  time.Duration
+		Ports    []uint16
+		Ids      []int
+		Retries  []time.Duration
+		Backends map[string]server
+	}
+	cfg := &typed{Timeout: 30 * time.Second, Ports: []uint16{80}}
+	cmd := &cobra.Command{Use: "app"}
+	if err := Bind(cmd, cfg); err != nil {
+		t.Fatalf("failed to bind, err: %s", err)
+	}
+	if cmd.Flags().Lookup("backends") != nil {
+		t.Errorf("a map of sections has no flag")
+	}
+
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("level: 1\n"), 0644)
+	err := cmd.Flags().Parse([]string{"--timeout=1m", "--ports=80,8080", "--ids=1,-2", "--retries=1s,2s"})
+	if err != nil {
+		t.Fatalf("failed to parse flags, err: %s", err)
+	}
+	if err := Load(cmd, cfg, path); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	expected := &typed{1, time.Minute, []uint16{80, 8080}, []int{1, -2}, []time.Duration{time.Second, 2 * time.Second}, nil}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("not expected config, output: %+v, expected: %+v", cfg, expected)
+	}
+
+	for _, arg := range []string{"--level=300", "--ports=70000", "--timeout=30"} {
+		cfg, cmd := &typed{}, &cobra.Command{Use: "app"}
+		Bind(cmd, cfg)
+		cmd.Flags().Parse([]string{arg})
+		if err := Load(cmd, cfg, path); !errors.Is(err, goconf.ErrTypeMismatch) {
+			t.Errorf("need a type error of %s, err: %v", arg, err)
+		}
+	}
+}
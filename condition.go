@@ -0,0 +1,109 @@
+/**
+ * Conditional sections hold items which apply only on some machines. The
+ * condition of a header '[if ...]' is evaluated while parsing, and the items
+ * of the section override the base items at the end of the file, if the
+ * condition holds. Keys are paths, as in profiles.
+ *
+ *      e.g.
+ *          > workers: 4
+ *          > [if host=web-*]
+ *          > workers: 16
+ *          > [if env GO_ENV=staging]
+ *          > db.host: db.staging
+ *
+ *      A condition matches the hostname, or an env variable, by a glob
+ *      pattern of path.Match. '!=' negates it.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 11:20:47
+ */
+
+package goconf
+
+import (
+	"errors"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	_COND_IF   = "if"
+	_COND_HOST = "host"
+	_COND_ENV  = "env"
+)
+
+// isCondition reports whether a section name is a condition 'if ...'.
+func isCondition(name string) bool {
+	fields := strings.Fields(name)
+	return len(fields) > 1 && fields[0] == _COND_IF
+}
+
+// evalCondition evaluates the condition of a section 'if ...', which is
+// 'if host=pattern' or 'if env NAME=pattern', or negated by '!='.
+func evalCondition(name string) (bool, error) {
+	fields := strings.Fields(name)[1:]
+	var subject string
+	if len(fields) == 2 && fields[0] == _COND_ENV {
+		subject = fields[1]
+	} else if len(fields) == 1 {
+		subject = fields[0]
+	} else {
+		return false, errors.New("need 'if host=pattern' or 'if env NAME=pattern', condition: " + name)
+	}
+
+	idx := strings.IndexByte(subject, '=')
+	if idx <= 0 {
+		return false, errors.New("need '=' or '!=' in condition: " + name)
+	}
+	negated := subject[idx-1] == '!'
+	lhs, pattern := subject[:idx], subject[idx+1:]
+	if negated {
+		lhs = lhs[:len(lhs)-1]
+	}
+
+	var val string
+	if len(fields) == 2 {
+		val = os.Getenv(lhs)
+	} else if lhs == _COND_HOST {
+		host, err := hostname()
+		if err != nil {
+			return false, err
+		}
+		val = host
+	} else {
+		return false, errors.New("unknown subject '" + lhs + "' of condition: " + name)
+	}
+
+	matched, err := path.Match(pattern, val)
+	if err != nil {
+		return false, errors.New("bad pattern '" + pattern + "' of condition: " + name)
+	}
+	return matched != negated, nil
+}
+
+// overrideItems sets the items of the conf by the items of sec, whose keys
+// are paths 'section.key' or global keys. Sections are created if absent.
+func (conf *Conf) overrideItems(sec *section) {
+	for _, o := range sec.items() {
+		target, key := conf.sections[_GLOBAL], o.key
+		if dot := strings.IndexByte(o.key, _PATH_SEP); dot > 0 {
+			secName := o.key[:dot]
+			if target = conf.sections[secName]; target == nil {
+				target = newSection()
+				conf.sections[secName] = target
+			}
+			key = o.key[dot+1:]
+		}
+
+		if target.get(key) != nil {
+			conf.logf("'%s' is overridden by %s", o.key, o.Source())
+		}
+		item := conf.putItem(target, key, o.val)
+		item.raw = o.raw
+		item.origin = o.origin
+		if o.sep != conf.opts.elementSep {
+			item.sep = o.sep // declared by '[@key@sep]'
+		}
+	}
+}
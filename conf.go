@@ -20,39 +20,128 @@
 
 package goconf
 
+//go:generate go run gen_panic.go
+
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"github.com/chosen0ne/goutils"
 	"io"
 	"os"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 )
 
 const (
 	_KV_SEP      = ':'
+	_KV_SEP_EQ   = '='
+	_APPEND_OP   = "+="
 	_NEWLINE     = '\n'
-	_SPACE_CHARS = " \t\n"
+	_CR          = '\r'
+	_BOM         = "\ufeff"
+	_SPACE_CHARS = " \t\r\n"
 	_GLOBAL      = "__global__"
 
 	_DEFAULT_SEP   = ' '
 	_SECTION_LEFT  = '['
 	_SECTION_RIGHT = ']'
+	_ARRAY_LEFT    = "[@"
+	_ARRAY_SEP_TAG = '@'
 	_COMMENT_TAG   = '#'
+	_PATH_SEP      = '.'
+	_DIRECTIVE_TAG = '!'
 )
 
-var (
-	elementSep byte
-)
+// GlobalSection is the name under which the global section is reported,
+// e.g. by Sections(true).
+const GlobalSection = _GLOBAL
+
+// defaultElementSep is the element separator of new Confs, see
+// SetElementSep.
+var defaultElementSep byte = _DEFAULT_SEP
 
 // 'section' is a group of config items. It can be used to
-// group the config items into a logic unit.
+// group the config items into a logic unit. Items are kept in the order
+// their keys are set first, i.e. the order of the file.
 //
 // NOTICE: In a config file, all items which aren't belonged
 //		to any sections are in the global section by default.
-type section map[string]*Item
+type section struct {
+	m    map[string]*Item
+	keys []string // keys of m, in order of first set
+}
+
+func newSection() *section {
+	return &section{m: make(map[string]*Item)}
+}
+
+// get returns item 'key', or nil if there's none. A nil section has no
+// items.
+func (s *section) get(key string) *Item {
+	if s == nil {
+		return nil
+	}
+	return s.m[key]
+}
+
+// set sets item 'key'. An item replacing another keeps its position.
+func (s *section) set(key string, item *Item) {
+	if _, ok := s.m[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.m[key] = item
+}
+
+// del removes item 'key', if any.
+func (s *section) del(key string) {
+	if _, ok := s.m[key]; !ok {
+		return
+	}
+	delete(s.m, key)
+	s.keys = slices.DeleteFunc(s.keys, func(k string) bool { return k == key })
+}
+
+// rename renames item 'key' to 'newKey' in its position.
+func (s *section) rename(key, newKey string) {
+	item, ok := s.m[key]
+	if !ok {
+		return
+	}
+	delete(s.m, key)
+	s.m[newKey] = item
+	s.keys[slices.Index(s.keys, key)] = newKey
+}
 
-func newSection() section {
-	return make(map[string]*Item)
+func (s *section) len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.keys)
+}
+
+// items returns the items of the section in order.
+func (s *section) items() []*Item {
+	if s == nil {
+		return nil
+	}
+	items := make([]*Item, len(s.keys))
+	for idx, key := range s.keys {
+		items[idx] = s.m[key]
+	}
+	return items
+}
+
+// sortedKeys returns the keys of the section by name.
+func (s *section) sortedKeys() []string {
+	if s == nil {
+		return nil
+	}
+	keys := slices.Clone(s.keys)
+	sort.Strings(keys)
+	return keys
 }
 
 // A Conf object can be parsed from a config file. Config items
@@ -64,49 +153,199 @@ func newSection() section {
 //		end of the file, as a section only has a start tag. So
 //		any global config items between sections will not be
 //		identified as global items.
+//
+// Once parsed, a Conf is safe for concurrent use by readers: the GetXxx and
+// GetXxxFrom getters, HasItem, Sections, Walk and loading config objects
+// from it. Methods which change the conf, e.g. Parse, Reload, Rollback,
+// Section, SetGlobalSection, ApplyPatch and Release, must not run
+// concurrently with any other method, except Changes. With
+// WithLazySections, the first read of a section parses it, so readers are
+// only safe once all the sections are parsed, e.g. by a Walk.
 type Conf struct {
-	filePath string             // path to the config file
-	sections map[string]section // all sections in a config file
-	eleSep   byte               // element seperator of array item
-	cur      section            // current section
+	filePath     string              // path to the config file
+	sections     map[string]*section // all sections in a config file
+	eleSep       byte                // element seperator of array item
+	cur          *section            // current section
+	includeDepth int                 // nesting level of included files while parsing
+	opts         *options
+	arena        *itemArena                   // nil if items are allocated one by one
+	mappings     [][]byte                     // files mapped by WithMmap
+	annotations  map[string]map[string]string // annotations of sections
+	defaults     map[string]*section          // default items by section, see SetDefaults
+	recording    *Recording                   // nil if access isn't recorded
+	itemCount    int                          // items parsed, counted by Limits.MaxItems
+	pending      map[string]*pendingSection   // sections not parsed yet, see WithLazySections
+	changesMu    sync.Mutex
+	changes      []chan ChangeEvent // channels of Changes
+	ctx          context.Context    // context of ParseContext while parsing, nil otherwise
+	logger       Logger
+	warnings     []Warning
+	warned       map[Warning]struct{} // set of warnings, see warn
+}
+
+func New(filePath string, opts ...Option) *Conf {
+	return newConf(filePath, newOptions(opts))
 }
 
-func New(filePath string) *Conf {
+// newConf creates a conf of file 'filePath' with options o.
+func newConf(filePath string, o *options) *Conf {
 	conf := &Conf{}
 	conf.filePath = filePath
-	conf.sections = make(map[string]section)
+	conf.sections = make(map[string]*section)
 	conf.cur = newSection()
 	conf.sections[_GLOBAL] = conf.cur
+	conf.opts = o
+	if conf.opts.itemArena {
+		conf.arena = &itemArena{}
+	}
 
 	return conf
 }
 
+// Release drops all the sections and items of the conf, so it's empty as
+// a new one. If the conf is created with WithItemArena, the memory of the
+// items is reused by later parsing, so the items got from the conf must
+// not be used any more. So are the strings got from a conf created with
+// WithMmap, as the file is unmapped.
+func (conf *Conf) Release() {
+	if conf.arena != nil {
+		conf.arena.release()
+	}
+	conf.unmap()
+
+	conf.sections = make(map[string]*section)
+	conf.cur = newSection()
+	conf.sections[_GLOBAL] = conf.cur
+	conf.annotations = nil
+	conf.itemCount = 0
+	conf.pending = nil
+	conf.warnings, conf.warned = nil, nil
+}
+
 func (conf *Conf) Parse() error {
+	return conf.parseFile(false)
+}
+
+// parseFile parses the config file. If merge is true, the file is an
+// overlay on the items parsed before.
+func (conf *Conf) parseFile(merge bool) error {
+	// Stat first to tell a directory from a config file
+	info, err := os.Stat(conf.filePath)
+	if err != nil {
+		return fileErr(conf.filePath, err)
+	} else if info.IsDir() {
+		return &FileError{Path: conf.filePath, Kind: ErrIsDirectory}
+	} else if max := conf.opts.limits.MaxFileSize; max > 0 && info.Size() > max {
+		return limitErr(conf.filePath, 0, "file exceeds %d bytes", max)
+	}
+
 	// Open config file
 	f, err := os.Open(conf.filePath)
 	if err != nil {
-		return goutils.WrapErr(err)
+		return fileErr(conf.filePath, err)
 	}
 
 	defer f.Close()
-	buf := bufio.NewReader(f)
 
-	if err := conf.parse(buf); err != nil {
+	parser, err := conf.formatParser(conf.filePath)
+	if err != nil {
 		return err
 	}
 
 	conf.cur = conf.sections[_GLOBAL]
+	if conf.opts.mmap && info.Size() > 0 && parser == nil {
+		err = conf.parseMapped(f, info.Size(), merge)
+	} else if conf.lazy(merge) && parser == nil {
+		err = conf.parseFileLazily(f)
+	} else {
+		err = conf.parseFormat(bufio.NewReader(f), conf.filePath, merge)
+	}
+	if err != nil {
+		return err
+	}
+
+	return conf.resolve()
+}
+
+// resolve finishes parsing: items are overridden by the profile and env
+// variables, values are interpolated, encrypted values are decrypted and
+// secret references are resolved, as the options ask.
+func (conf *Conf) resolve() error {
+	conf.cur = conf.sections[_GLOBAL]
+	if err := conf.applyProfile(); err != nil {
+		return err
+	}
+	if conf.opts.envPrefix != nil {
+		conf.OverrideFromEnv(*conf.opts.envPrefix)
+	}
+	if conf.opts.interpolation {
+		if err := conf.interpolate(); err != nil {
+			return err
+		}
+	}
+	if conf.opts.decryptor != nil {
+		if err := conf.decrypt(conf.opts.decryptor); err != nil {
+			return err
+		}
+	}
+	if len(conf.opts.secretResolvers) != 0 {
+		return conf.resolveSecrets(conf.opts.secretResolvers)
+	}
 
 	return nil
 }
 
+// parseFormat parses buf by the format parser registered for the extension
+// of file 'path', or as a goconf file if there's none.
+func (conf *Conf) parseFormat(buf *bufio.Reader, path string, merge bool) error {
+	parser, err := conf.formatParser(path)
+	if err != nil {
+		return err
+	}
+	if parser != nil {
+		return conf.parseWith(parser, buf, path)
+	}
+
+	return conf.parseFrom(buf, path, merge)
+}
+
+// Exists reports whether the config file exists and is a regular file,
+// so callers can tell an absent optional config before Parse.
+func (conf *Conf) Exists() bool {
+	info, err := os.Stat(conf.filePath)
+	return err == nil && !info.IsDir()
+}
+
 func (conf *Conf) parse(buf *bufio.Reader) error {
+	return conf.parseFrom(buf, conf.filePath, false)
+}
+
+// parseFrom reads the config lines of file 'path' from buf. If merge is
+// true, sections which already exist are reopened instead of being
+// reported as duplicated, which is how included files override items.
+func (conf *Conf) parseFrom(buf lineReader, path string, merge bool) error {
+	return conf.parseFromLine(buf, path, 0, merge)
+}
+
+// parseFromLine parses buf as parseFrom does, where buf starts after line
+// lineNo of the file.
+func (conf *Conf) parseFromLine(buf lineReader, path string, lineNo int, merge bool) error {
+	buf = conf.newLineReader(buf, path)
+	var annotations map[string]string // annotations of the next line
+	curName := conf.sectionName(conf.cur)
+	var seen map[string]bool // items set by the file, to find duplicate keys
+	if conf.opts.duplicateKeys != DuplicateLastWins {
+		seen = make(map[string]bool)
+	}
+	var conds []*section // conditional sections whose conditions hold
+	headerLine := 0      // line of the header of the current section
 	for {
 		line, err := buf.ReadString(_NEWLINE)
+		lineNo++
 		if len(line) == 0 && err == io.EOF {
-			return nil
+			break
 		} else if err != nil && err != io.EOF {
-			return goutils.WrapErr(err)
+			return readErr(err)
 		}
 
 		// Trim space chars
@@ -114,6 +353,7 @@ func (conf *Conf) parse(buf *bufio.Reader) error {
 
 		// Found an empty line
 		if len(lineStr) == 0 {
+			annotations = nil
 			continue
 		}
 
@@ -122,76 +362,396 @@ func (conf *Conf) parse(buf *bufio.Reader) error {
 			lineStr = lineStr[:len(lineStr)-1]
 		}
 
+		// Found an annotation line
+		if isAnnotation(lineStr) {
+			annotations = addAnnotation(annotations, lineStr)
+			continue
+		}
+
 		// Found a comment line
 		if lineStr[0] == _COMMENT_TAG {
+			annotations = nil
+			continue
+		}
+
+		// Found a directive line
+		if lineStr[0] == _DIRECTIVE_TAG {
+			annotations = nil
+			if err := conf.directive(lineStr[1:], path, lineNo); err != nil {
+				return err
+			}
 			continue
 		}
 
 		if isSection(lineStr) {
 			sectionName := strings.Trim(lineStr[1:len(lineStr)-1], _SPACE_CHARS)
-			if _, ok := conf.sections[sectionName]; ok {
-				return goutils.NewErr("section '%s' already exist", sectionName)
+			if isCondition(sectionName) {
+				holds, err := evalCondition(sectionName)
+				if err != nil {
+					return parseErr(path, lineNo, E_PARSE_CONDITION, "%s", err)
+				}
+				annotations = nil
+				curName, headerLine = sectionName, lineNo
+				conf.cur = newSection()
+				if holds {
+					conds = append(conds, conf.cur)
+				}
+				continue
+			}
+			sectionName, parent := splitParent(sectionName)
+			conf.annotateSection(sectionName, annotations)
+			annotations = nil
+			curName, headerLine = sectionName, lineNo
+			if s, ok := conf.sections[sectionName]; ok {
+				if !merge {
+					return parseErr(path, lineNo, E_DUP_SECTION, "section '%s' already exist", sectionName)
+				}
+				conf.cur = s
+			} else {
+				// A new section, the following config items belongs to the section.
+				conf.cur = newSection()
+				conf.sections[sectionName] = conf.cur
+				if err := conf.checkSections(path, lineNo); err != nil {
+					return err
+				}
+			}
+			if parent != "" {
+				if err := conf.inherit(conf.cur, sectionName, parent, path, lineNo); err != nil {
+					return err
+				}
 			}
-
-			// A new section, the following config items belongs to the section
-			conf.cur = newSection()
-			conf.sections[sectionName] = conf.cur
 		} else {
-			// Find 'Key : Value'
-			parts := strings.SplitN(lineStr, string(_KV_SEP), 2)
-			if len(parts) != 2 {
-				return goutils.NewErr("need ':' in a line, line: %s", lineStr)
+			start := lineNo
+			text := line // the text of the item, lineStr with its spaces
+			if endsWithEscape(lineStr) {
+				if lineStr, err = continueLine(buf, lineStr, &lineNo); err != nil {
+					return err
+				}
+				text = lineStr
+			}
+
+			sep, sepLen, appending := conf.itemSep(lineStr)
+			if sep < 0 {
+				return parseErr(path, start, E_PARSE_NO_SEP, "need %s in a line, line: %s",
+					kvSepName(conf.opts.kvSep), lineStr)
+			}
+			key, eleSep, err := parseArrayKey(strings.Trim(lineStr[:sep], _SPACE_CHARS), conf.opts.elementSep)
+			if err != nil {
+				return parseErr(path, start, E_PARSE_ARRAY, "%s", err)
+			}
+			val := strings.Trim(lineStr[sep+sepLen:], _SPACE_CHARS)
+			raw := rawValue(text, lineStr, sep+sepLen)
+			if marker, ok := heredocMarker(val); ok {
+				raw = ""
+				if val, ok, err = readHeredoc(buf, marker, &lineNo); err != nil {
+					return err
+				} else if !ok {
+					return parseErr(path, start, E_PARSE_HEREDOC, "no end '%s' of '%s'", marker, key)
+				}
 			}
-			key := strings.Trim(parts[0], _SPACE_CHARS)
-			val := strings.Trim(parts[1], _SPACE_CHARS)
 			if len(val) == 0 {
-				return goutils.NewErr("an empty value")
+				if !conf.opts.skipEmpty {
+					return parseErr(path, start, E_PARSE_EMPTY_VALUE, "an empty value of '%s'", key)
+				}
+				conf.warn(origin{file: path, line: start}, itemPath(curName, key), "an empty value is skipped")
+				annotations = nil
+				continue
+			}
+
+			old := conf.cur.get(key)
+			if old == nil && conf.opts.limits.MaxItems > 0 {
+				if err := conf.countItem(path, start); err != nil {
+					return err
+				}
 			}
 
-			conf.cur[key] = &Item{key, val}
+			var item *Item
+			itemName := key // the path is only needed by duplicates and merge strategies
+			if seen != nil || len(conf.opts.mergeStrategies) != 0 {
+				itemName = itemPath(curName, key)
+			}
+			if old != nil && !appending {
+				// Without seen, a key repeated in the section is told by
+				// the origin of the former item
+				duplicate := seen[itemName]
+				if duplicate || (seen == nil && old.origin.file == path && old.origin.line > headerLine) {
+					conf.warnDuplicate(origin{file: path, line: start}, itemPath(curName, key), old)
+				}
+				if conf.logger != nil && (!duplicate || conf.opts.duplicateKeys != DuplicateError) {
+					conf.logSetAgain(itemPath(curName, key), old, path, start, duplicate)
+				}
+			}
+			if appending {
+				item = conf.appendItem(conf.cur, key, val)
+				if seen != nil {
+					seen[itemName] = true
+				}
+			} else if seen != nil && seen[itemName] {
+				if conf.opts.duplicateKeys == DuplicateError {
+					return parseErr(path, start, E_DUP_KEY, "duplicate key '%s'", itemName)
+				}
+				if item = conf.setDuplicate(conf.cur, key, val); item == nil {
+					annotations = nil
+					continue
+				}
+			} else if item, err = conf.setItem(conf.cur, itemName, key, val); err != nil {
+				return parseErr(path, start, E_MERGE, "%s", err)
+			} else if seen != nil {
+				seen[itemName] = true
+			}
+			if eleSep != 0 {
+				item.sep = eleSep
+			}
+			if raw != val && item.val == val {
+				item.raw = raw
+			}
+			item.origin = origin{file: path, line: start}
+			item.annotations = annotations
+			annotations = nil
 		}
 	}
 
+	// Conditional sections override the items of the whole file
+	for _, sec := range conds {
+		conf.overrideItems(sec)
+	}
+
 	return nil
 }
 
+// rawValue returns the value of an item as written: the text after offset
+// off of lineStr, which is text with its spaces trimmed, without the line
+// end.
+func rawValue(text, lineStr string, off int) string {
+	lead := len(text) - len(strings.TrimLeft(text, _SPACE_CHARS))
+	return strings.TrimRight(text[lead+off:], "\r\n")
+}
+
+// itemSep finds the separator of 'Key : Value', or 'Key += Value' if
+// appending. sep is -1 if there's none.
+func (conf *Conf) itemSep(line string) (sep, sepLen int, appending bool) {
+	sep, sepLen = kvSepIndex(line, conf.opts.kvSep), 1
+	if idx := strings.Index(line, _APPEND_OP); idx >= 0 && (sep < 0 || idx < sep) {
+		return idx, len(_APPEND_OP), true
+	}
+	return sep, sepLen, false
+}
+
+// continueLine joins line, which ends with '\\', with its continuation
+// lines. The '\\' and leading spaces of continuation lines are removed, e.g.
+// 'cmd: run \\' and '    --fast' make 'cmd: run --fast'.
+func continueLine(buf lineReader, line string, lineNo *int) (string, error) {
+	var sb strings.Builder
+	for endsWithEscape(line) {
+		sb.WriteString(line[:len(line)-1])
+		next, err := buf.ReadString(_NEWLINE)
+		if len(next) == 0 && err == io.EOF {
+			return sb.String(), nil
+		} else if err != nil && err != io.EOF {
+			return "", readErr(err)
+		}
+		*lineNo++
+		line = strings.Trim(next, _SPACE_CHARS)
+	}
+	sb.WriteString(line)
+
+	return sb.String(), nil
+}
+
+// GetItem returns item 'key' of the current section. If there's no such
+// item, 'key' is taken as a path 'section.key', so "server.port" reads
+// 'port' of section 'server' without changing the current section.
 func (conf *Conf) GetItem(key string) (*Item, error) {
-	item, ok := conf.cur[key]
+	item, ok := conf.lookup(conf.cur, key)
+	if conf.recording != nil {
+		conf.record(conf.sectionName(conf.cur), key, item)
+	}
 	if !ok {
-		return nil, goutils.NewErr("non-exist item: %s", key)
+		if err := conf.pathErr(key); err != nil {
+			return nil, err
+		}
+		return nil, keyNotFound(key)
 	}
 	return item, nil
 }
 
 func (conf *Conf) HasItem(key string) bool {
-	_, ok := conf.cur[key]
+	_, ok := conf.lookup(conf.cur, key)
 	return ok
 }
 
+// lookup finds 'key' in section 'cur', or by path 'section.key'. Items
+// missing from the file are looked up in the defaults, and then in the
+// global section by WithGlobalFallback.
+func (conf *Conf) lookup(cur *section, key string) (*Item, bool) {
+	if item := cur.get(key); item != nil {
+		return item, true
+	}
+
+	if dot := strings.IndexByte(key, _PATH_SEP); dot > 0 {
+		conf.loadSection(key[:dot])
+		if item := conf.sections[key[:dot]].get(key[dot+1:]); item != nil {
+			return item, true
+		}
+	}
+
+	if conf.defaults != nil {
+		if item, ok := conf.lookupDefault(cur, key); ok {
+			return item, true
+		}
+	}
+	return conf.lookupGlobal(key)
+}
+
+// lookupGlobal finds the global item 'key' by WithGlobalFallback.
+func (conf *Conf) lookupGlobal(key string) (*Item, bool) {
+	if !conf.opts.globalFallback {
+		return nil, false
+	}
+	if item := conf.sections[_GLOBAL].get(key); item != nil {
+		return item, true
+	}
+	item := conf.defaults[_GLOBAL].get(key)
+	return item, item != nil
+}
+
+// Items returns the items of the current section in the order of the file.
 func (conf *Conf) Items() []*Item {
-	items := make([]*Item, len(conf.cur))
-	idx := 0
-	for _, v := range conf.cur {
-		items[idx] = v
-		idx++
+	return conf.cur.items()
+}
+
+// SectionItems returns the items of section 'name' in the order of the
+// file, without changing the current section.
+func (conf *Conf) SectionItems(name string) ([]*Item, error) {
+	if err := conf.loadSection(name); err != nil {
+		return nil, err
+	}
+	section, ok := conf.sections[name]
+	if !ok {
+		return nil, sectionNotFound(name)
 	}
 
-	return items
+	return section.items(), nil
+}
+
+// GetItemFrom returns item 'key' of section 'sectionName' without changing
+// the current section, so it's safe to read from several sections
+// concurrently, unless the section is still pending by WithLazySections.
+// The GetXxxFrom family is built on top of it.
+func (conf *Conf) GetItemFrom(sectionName, key string) (*Item, error) {
+	if err := conf.loadSection(sectionName); err != nil {
+		return nil, err
+	}
+	section, ok := conf.sections[sectionName]
+	item := section.get(key)
+	if item == nil && conf.defaults != nil {
+		item = conf.defaults[sectionName].get(key)
+		ok = ok || item != nil
+	}
+	if item == nil && ok {
+		item, _ = conf.lookupGlobal(key)
+	}
+	if conf.recording != nil {
+		conf.record(sectionName, key, item)
+	}
+	if !ok {
+		return nil, sectionNotFound(sectionName)
+	}
+
+	if item == nil {
+		return nil, keyNotFound(sectionName + string(_PATH_SEP) + key)
+	}
+	return item, nil
+}
+
+func (conf *Conf) GetIntFrom(sectionName, key string) (int64, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return -1, err
+	}
+
+	return item.ToInt()
+}
+
+func (conf *Conf) GetFloatFrom(sectionName, key string) (float64, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return -1, err
+	}
+
+	return item.ToFloat()
+}
+
+func (conf *Conf) GetStringFrom(sectionName, key string) (string, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return "", err
+	}
+
+	return item.val, nil
+}
+
+func (conf *Conf) GetIntArrayFrom(sectionName, key string) ([]int64, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToIntArray()
+}
+
+func (conf *Conf) GetFloatArrayFrom(sectionName, key string) ([]float64, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToFloatArray()
+}
+
+func (conf *Conf) GetStringArrayFrom(sectionName, key string) ([]string, error) {
+	item, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToStringArray(), nil
 }
 
 func (conf *Conf) GetInt(key string) (int64, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return -1, goutils.WrapErr(err)
+		return -1, err
 	}
 
 	return item.ToInt()
 }
 
+// GetPercent returns item 'key' as a ratio, e.g. 0.75 of '75%', see
+// Item.ToPercent.
+func (conf *Conf) GetPercent(key string) (float64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.ToPercent()
+}
+
+// GetUint returns item 'key' as an unsigned int, see Item.ToUint.
+func (conf *Conf) GetUint(key string) (uint64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return item.ToUint()
+}
+
 func (conf *Conf) GetFloat(key string) (float64, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return -1, goutils.WrapErr(err)
+		return -1, err
 	}
 
 	return item.ToFloat()
@@ -200,16 +760,26 @@ func (conf *Conf) GetFloat(key string) (float64, error) {
 func (conf *Conf) GetString(key string) (string, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return "", goutils.WrapErr(err)
+		return "", err
 	}
 
 	return item.val, nil
 }
 
+// GetRaw returns item 'key' as written in the file, see Item.Raw.
+func (conf *Conf) GetRaw(key string) (string, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return "", err
+	}
+
+	return item.Raw(), nil
+}
+
 func (conf *Conf) GetIntArray(key string) ([]int64, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return nil, goutils.WrapErr(err)
+		return nil, err
 	}
 
 	return item.ToIntArray()
@@ -218,7 +788,7 @@ func (conf *Conf) GetIntArray(key string) ([]int64, error) {
 func (conf *Conf) GetFloatArray(key string) ([]float64, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return nil, goutils.WrapErr(err)
+		return nil, err
 	}
 
 	return item.ToFloatArray()
@@ -227,19 +797,282 @@ func (conf *Conf) GetFloatArray(key string) ([]float64, error) {
 func (conf *Conf) GetStringArray(key string) ([]string, error) {
 	item, err := conf.GetItem(key)
 	if err != nil {
-		return nil, goutils.WrapErr(err)
+		return nil, err
 	}
 
 	return item.ToStringArray(), nil
 }
 
+// GetBoolArray returns item 'key' as bools, see Item.ToBoolArray.
+func (conf *Conf) GetBoolArray(key string) ([]bool, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToBoolArray()
+}
+
+// GetStringArraySep returns item 'key' split by sep, e.g. ',' or '|',
+// instead of the element separator.
+func (conf *Conf) GetStringArraySep(key, sep string) ([]string, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToStringArraySep(sep), nil
+}
+
+// GetIntArraySep is like GetIntArray, but splits the value by sep.
+func (conf *Conf) GetIntArraySep(key, sep string) ([]int64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToIntArraySep(sep)
+}
+
+// GetFloatArraySep is like GetFloatArray, but splits the value by sep.
+func (conf *Conf) GetFloatArraySep(key, sep string) ([]float64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToFloatArraySep(sep)
+}
+
+// GetBytes returns item 'key' decoded as base64, see Item.ToBytes.
+func (conf *Conf) GetBytes(key string) ([]byte, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToBytes()
+}
+
+// The GetXxxOK getters are like GetXxx, but report an absent or malformed
+// item by false instead of an error, so optional items are read without
+// allocating errors.
+
+// itemOK is GetItem without an error.
+func (conf *Conf) itemOK(key string) (*Item, bool) {
+	item, ok := conf.lookup(conf.cur, key)
+	if conf.recording != nil {
+		conf.record(conf.sectionName(conf.cur), key, item)
+	}
+	return item, ok
+}
+
+func (conf *Conf) GetIntOK(key string) (int64, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return 0, false
+	}
+
+	val, err := parseInt(item.val)
+	return val, err == nil
+}
+
+func (conf *Conf) GetFloatOK(key string) (float64, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return 0, false
+	}
+
+	val, err := parseFloat(item.val)
+	return val, err == nil
+}
+
+func (conf *Conf) GetStringOK(key string) (string, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return "", false
+	}
+
+	return item.val, true
+}
+
+func (conf *Conf) GetIntArrayOK(key string) ([]int64, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false
+	}
+
+	vals, err := item.ToIntArray()
+	return vals, err == nil
+}
+
+func (conf *Conf) GetFloatArrayOK(key string) ([]float64, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false
+	}
+
+	vals, err := item.ToFloatArray()
+	return vals, err == nil
+}
+
+func (conf *Conf) GetStringArrayOK(key string) ([]string, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false
+	}
+
+	return item.ToStringArray(), true
+}
+
+// The GetXxxDefault getters are like GetXxxOK, but return def for an absent
+// or malformed item, e.g. conf.GetIntDefault("port", 8080).
+
+func (conf *Conf) GetIntDefault(key string, def int64) int64 {
+	if val, ok := conf.GetIntOK(key); ok {
+		return val
+	}
+	return def
+}
+
+func (conf *Conf) GetFloatDefault(key string, def float64) float64 {
+	if val, ok := conf.GetFloatOK(key); ok {
+		return val
+	}
+	return def
+}
+
+func (conf *Conf) GetStringDefault(key string, def string) string {
+	if val, ok := conf.GetStringOK(key); ok {
+		return val
+	}
+	return def
+}
+
+func (conf *Conf) GetBoolDefault(key string, def bool) bool {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return def
+	}
+	if val, err := item.ToBool(); err == nil {
+		return val
+	}
+	return def
+}
+
+func (conf *Conf) GetIntArrayDefault(key string, def []int64) []int64 {
+	if vals, ok := conf.GetIntArrayOK(key); ok {
+		return vals
+	}
+	return def
+}
+
+func (conf *Conf) GetFloatArrayDefault(key string, def []float64) []float64 {
+	if vals, ok := conf.GetFloatArrayOK(key); ok {
+		return vals
+	}
+	return def
+}
+
+func (conf *Conf) GetStringArrayDefault(key string, def []string) []string {
+	if vals, ok := conf.GetStringArrayOK(key); ok {
+		return vals
+	}
+	return def
+}
+
+// The LookupXxx getters are like GetXxx, but report an absent item by
+// false without an error. So a missing item is told from a malformed one,
+// which is present and reported by a *TypeError.
+
+func (conf *Conf) LookupInt(key string) (int64, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	val, err := item.ToInt()
+	return val, true, err
+}
+
+func (conf *Conf) LookupUint(key string) (uint64, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	val, err := item.ToUint()
+	return val, true, err
+}
+
+func (conf *Conf) LookupFloat(key string) (float64, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return 0, false, nil
+	}
+
+	val, err := item.ToFloat()
+	return val, true, err
+}
+
+func (conf *Conf) LookupString(key string) (string, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return "", false
+	}
+
+	return item.val, true
+}
+
+func (conf *Conf) LookupBool(key string) (bool, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return false, false, nil
+	}
+
+	val, err := item.ToBool()
+	return val, true, err
+}
+
+func (conf *Conf) LookupIntArray(key string) ([]int64, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	vals, err := item.ToIntArray()
+	return vals, true, err
+}
+
+func (conf *Conf) LookupFloatArray(key string) ([]float64, bool, error) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	vals, err := item.ToFloatArray()
+	return vals, true, err
+}
+
+func (conf *Conf) LookupStringArray(key string) ([]string, bool) {
+	item, ok := conf.itemOK(key)
+	if !ok {
+		return nil, false
+	}
+
+	return item.ToStringArray(), true
+}
+
 func (conf *Conf) Section(name string) error {
+	if err := conf.loadSection(name); err != nil {
+		return err
+	}
 	if section, ok := conf.sections[name]; ok {
 		conf.cur = section
 		return nil
 	}
 
-	return goutils.NewErr("no section '%s'", name)
+	return sectionNotFound(name)
 }
 
 func (conf *Conf) HasSection(name string) bool {
@@ -247,13 +1080,120 @@ func (conf *Conf) HasSection(name string) bool {
 	return ok
 }
 
+// Sections returns the names of all sections in the config file, sorted
+// by name. The global section is included as GlobalSection only if
+// withGlobal is true.
+func (conf *Conf) Sections(withGlobal bool) []string {
+	names := make([]string, 0, len(conf.sections))
+	for name := range conf.sections {
+		if name == _GLOBAL && !withGlobal {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Walk calls fn for every item with the name of its section, visiting the
+// global section first, then the other sections in order of Sections.
+// Items of a section are visited in the order of the file. Walk stops at
+// the first error returned by fn, and returns it.
+func (conf *Conf) Walk(fn func(section string, item *Item) error) error {
+	if err := conf.loadSections(); err != nil {
+		return err
+	}
+
+	names := append([]string{_GLOBAL}, conf.Sections(false)...)
+	for _, name := range names {
+		for _, item := range conf.sections[name].items() {
+			if err := fn(name, item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// InferTypes infers the type of every item by Item.InferType, the same
+// inference as ToJSON uses. Global items are named by their keys, and the
+// others by 'section.key'.
+func (conf *Conf) InferTypes() map[string]string {
+	types := make(map[string]string)
+	conf.Walk(func(section string, item *Item) error {
+		types[itemPath(section, item.key)] = item.InferType()
+		return nil
+	})
+
+	return types
+}
+
 func (conf *Conf) SetGlobalSection() {
 	conf.cur = conf.sections[_GLOBAL]
 }
 
-// SetElementSep: set the separator of elements in an array
+// SetElementSep sets the element separator of arrays of the Confs created
+// later. It must be called before any Conf is created.
+//
+// Deprecated: use WithElementSep, which doesn't affect other Confs.
 func SetElementSep(sep byte) {
-	elementSep = sep
+	defaultElementSep = sep
+}
+
+// parseArrayKey parses key '[@name]' or '[@name@sep]' of an array item,
+// whose separator is sep, or def without '@sep'. For other keys, name is the
+// key and sep is 0.
+func parseArrayKey(key string, def byte) (name string, sep byte, err error) {
+	if !strings.HasPrefix(key, _ARRAY_LEFT) || key[len(key)-1] != _SECTION_RIGHT {
+		return key, 0, nil
+	}
+
+	name, sep = key[len(_ARRAY_LEFT):len(key)-1], def
+	if idx := strings.IndexByte(name, _ARRAY_SEP_TAG); idx >= 0 {
+		if len(name)-idx != 2 {
+			return "", 0, goutils.NewErr("the separator of '%s' must be a single char", key)
+		}
+		name, sep = name[:idx], name[idx+1]
+	}
+	if name = strings.Trim(name, _SPACE_CHARS); name == "" {
+		return "", 0, goutils.NewErr("no name in '%s'", key)
+	}
+
+	return name, sep, nil
+}
+
+// kvSepIndex returns the index of the key/value separator in line, or -1.
+// If sep is AutoKVSeparator, it's the first ':' or '='.
+func kvSepIndex(line string, sep byte) int {
+	if sep != AutoKVSeparator {
+		return strings.IndexByte(line, sep)
+	}
+
+	return strings.IndexAny(line, string([]byte{_KV_SEP, _KV_SEP_EQ}))
+}
+
+func kvSepName(sep byte) string {
+	if sep == AutoKVSeparator {
+		return fmt.Sprintf("'%c' or '%c'", _KV_SEP, _KV_SEP_EQ)
+	}
+	return fmt.Sprintf("'%c'", sep)
+}
+
+// itemPath names an item by its key in the global section, or by
+// 'section.key' in other sections.
+func itemPath(section, key string) string {
+	if section == _GLOBAL {
+		return key
+	}
+	return section + string(_PATH_SEP) + key
+}
+
+// parseErr creates a ParseError at the position in the config file,
+// e.g. "app.conf:42: need ':' in a line".
+func parseErr(path string, lineNo int, code Code, format string, args ...interface{}) error {
+	return &ParseError{Code: code, File: path, Line: lineNo, Msg: fmt.Sprintf(format, args...)}
 }
 
 func isSection(line string) bool {
@@ -263,7 +1203,3 @@ func isSection(line string) bool {
 
 	return false
 }
-
-func init() {
-	elementSep = _DEFAULT_SEP
-}
@@ -0,0 +1,298 @@
+// Code generated by 'go run gen_panic.go'; DO NOT EDIT.
+
+package goconf
+
+import (
+	"crypto/tls"
+	"net"
+	"net/url"
+	"regexp"
+)
+
+// ToBoolArray is like GetBoolArray, but panics on error.
+func (conf *Conf) ToBoolArray(key string) []bool {
+	val, err := conf.GetBoolArray(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToBytes is like GetBytes, but panics on error.
+func (conf *Conf) ToBytes(key string) []byte {
+	val, err := conf.GetBytes(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToCIDR is like GetCIDR, but panics on error.
+func (conf *Conf) ToCIDR(key string) *net.IPNet {
+	val, err := conf.GetCIDR(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloat is like GetFloat, but panics on error.
+func (conf *Conf) ToFloat(key string) float64 {
+	val, err := conf.GetFloat(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloatArray is like GetFloatArray, but panics on error.
+func (conf *Conf) ToFloatArray(key string) []float64 {
+	val, err := conf.GetFloatArray(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloatArrayFrom is like GetFloatArrayFrom, but panics on error.
+func (conf *Conf) ToFloatArrayFrom(sectionName string, key string) []float64 {
+	val, err := conf.GetFloatArrayFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloatArraySep is like GetFloatArraySep, but panics on error.
+func (conf *Conf) ToFloatArraySep(key string, sep string) []float64 {
+	val, err := conf.GetFloatArraySep(key, sep)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloatFrom is like GetFloatFrom, but panics on error.
+func (conf *Conf) ToFloatFrom(sectionName string, key string) float64 {
+	val, err := conf.GetFloatFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToFloatMatrix is like GetFloatMatrix, but panics on error.
+func (conf *Conf) ToFloatMatrix(key string) [][]float64 {
+	val, err := conf.GetFloatMatrix(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIDSet is like GetIDSet, but panics on error.
+func (conf *Conf) ToIDSet(key string) *IDSet {
+	val, err := conf.GetIDSet(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIP is like GetIP, but panics on error.
+func (conf *Conf) ToIP(key string) net.IP {
+	val, err := conf.GetIP(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToInt is like GetInt, but panics on error.
+func (conf *Conf) ToInt(key string) int64 {
+	val, err := conf.GetInt(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIntArray is like GetIntArray, but panics on error.
+func (conf *Conf) ToIntArray(key string) []int64 {
+	val, err := conf.GetIntArray(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIntArrayFrom is like GetIntArrayFrom, but panics on error.
+func (conf *Conf) ToIntArrayFrom(sectionName string, key string) []int64 {
+	val, err := conf.GetIntArrayFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIntArraySep is like GetIntArraySep, but panics on error.
+func (conf *Conf) ToIntArraySep(key string, sep string) []int64 {
+	val, err := conf.GetIntArraySep(key, sep)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIntFrom is like GetIntFrom, but panics on error.
+func (conf *Conf) ToIntFrom(sectionName string, key string) int64 {
+	val, err := conf.GetIntFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToIntMatrix is like GetIntMatrix, but panics on error.
+func (conf *Conf) ToIntMatrix(key string) [][]int64 {
+	val, err := conf.GetIntMatrix(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToItem is like GetItem, but panics on error.
+func (conf *Conf) ToItem(key string) *Item {
+	val, err := conf.GetItem(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToItemFrom is like GetItemFrom, but panics on error.
+func (conf *Conf) ToItemFrom(sectionName string, key string) *Item {
+	val, err := conf.GetItemFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToPercent is like GetPercent, but panics on error.
+func (conf *Conf) ToPercent(key string) float64 {
+	val, err := conf.GetPercent(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToRaw is like GetRaw, but panics on error.
+func (conf *Conf) ToRaw(key string) string {
+	val, err := conf.GetRaw(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToRegexp is like GetRegexp, but panics on error.
+func (conf *Conf) ToRegexp(key string) *regexp.Regexp {
+	val, err := conf.GetRegexp(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToString is like GetString, but panics on error.
+func (conf *Conf) ToString(key string) string {
+	val, err := conf.GetString(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringArray is like GetStringArray, but panics on error.
+func (conf *Conf) ToStringArray(key string) []string {
+	val, err := conf.GetStringArray(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringArrayFrom is like GetStringArrayFrom, but panics on error.
+func (conf *Conf) ToStringArrayFrom(sectionName string, key string) []string {
+	val, err := conf.GetStringArrayFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringArraySep is like GetStringArraySep, but panics on error.
+func (conf *Conf) ToStringArraySep(key string, sep string) []string {
+	val, err := conf.GetStringArraySep(key, sep)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringFrom is like GetStringFrom, but panics on error.
+func (conf *Conf) ToStringFrom(sectionName string, key string) string {
+	val, err := conf.GetStringFrom(sectionName, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringMap is like GetStringMap, but panics on error.
+func (conf *Conf) ToStringMap(key string) map[string]string {
+	val, err := conf.GetStringMap(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToStringMatrix is like GetStringMatrix, but panics on error.
+func (conf *Conf) ToStringMatrix(key string) [][]string {
+	val, err := conf.GetStringMatrix(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToTLSConfig is like GetTLSConfig, but panics on error.
+func (conf *Conf) ToTLSConfig(sectionName string) *tls.Config {
+	val, err := conf.GetTLSConfig(sectionName)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToURL is like GetURL, but panics on error.
+func (conf *Conf) ToURL(key string) *url.URL {
+	val, err := conf.GetURL(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// ToUint is like GetUint, but panics on error.
+func (conf *Conf) ToUint(key string) uint64 {
+	val, err := conf.GetUint(key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
@@ -0,0 +1,79 @@
+/**
+ * FakeClock is a goconf.Clock which only moves when Advance is called.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 13:41:20
+ */
+
+package conftest
+
+import (
+	"sync"
+	"time"
+)
+
+type waiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{now: now}
+	clock.cond = sync.NewCond(&clock.mu)
+	return clock
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *FakeClock) After(d time.Duration) <-chan time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	w := &waiter{clock.now.Add(d), make(chan time.Time, 1)}
+	if d <= 0 {
+		w.c <- clock.now
+		return w.c
+	}
+	clock.waiters = append(clock.waiters, w)
+	clock.cond.Broadcast()
+
+	return w.c
+}
+
+// Advance moves the clock forward by d, and fires every timer which is due.
+func (clock *FakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	clock.now = clock.now.Add(d)
+	pending := clock.waiters[:0]
+	for _, w := range clock.waiters {
+		if w.deadline.After(clock.now) {
+			pending = append(pending, w)
+		} else {
+			w.c <- clock.now
+		}
+	}
+	clock.waiters = pending
+}
+
+// BlockUntil waits until at least n timers are pending.
+func (clock *FakeClock) BlockUntil(n int) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	for len(clock.waiters) < n {
+		clock.cond.Wait()
+	}
+}
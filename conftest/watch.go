@@ -0,0 +1,142 @@
+/**
+ * WatchHarness drives a goconf.Watcher deterministically: the watched file
+ * lives in a temp dir, and polls only happen on Tick, which returns after
+ * the callbacks of the poll are delivered.
+ *
+ *      e.g.
+ *          h := conftest.NewWatchHarness(t, "port: 80\n", time.Second)
+ *          h.Write("port: 8080\n")
+ *          h.Tick()
+ *          ev := h.ExpectReload()
+ *          // check ev.Old and ev.New
+ *          h.Tick()
+ *          h.ExpectNoEvent()
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 13:52:09
+ */
+
+package conftest
+
+import (
+	"github.com/chosen0ne/goconf"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const _MAX_EVENTS = 64
+
+// An Event is a callback delivered by the watcher: either a reload with
+// Old and New set, or an error with Err set.
+type Event struct {
+	Old *goconf.Conf
+	New *goconf.Conf
+	Err error
+}
+
+type WatchHarness struct {
+	Path    string
+	Clock   *FakeClock
+	Watcher *goconf.Watcher
+
+	t        testing.TB
+	interval time.Duration
+	events   chan Event
+}
+
+// NewWatchHarness writes content to a temp file, and starts watching it.
+// The watcher is stopped when the test finishes.
+func NewWatchHarness(t testing.TB, content string, interval time.Duration) *WatchHarness {
+	t.Helper()
+
+	h := &WatchHarness{
+		Path:     filepath.Join(t.TempDir(), "watched.conf"),
+		Clock:    NewFakeClock(time.Unix(0, 0)),
+		t:        t,
+		interval: interval,
+		events:   make(chan Event, _MAX_EVENTS),
+	}
+	h.Write(content)
+
+	h.Watcher = goconf.NewWatcher(h.Path, interval)
+	h.Watcher.SetClock(h.Clock)
+	h.Watcher.OnChange(func(old, cur *goconf.Conf) {
+		h.events <- Event{Old: old, New: cur}
+	})
+	h.Watcher.OnError(func(err error) {
+		h.events <- Event{Err: err}
+	})
+	if err := h.Watcher.Start(); err != nil {
+		t.Fatalf("failed to start watcher, err: %s", err)
+	}
+	t.Cleanup(h.Watcher.Stop)
+
+	return h
+}
+
+// Write replaces the content of the watched file.
+func (h *WatchHarness) Write(content string) {
+	h.t.Helper()
+	if err := os.WriteFile(h.Path, []byte(content), 0644); err != nil {
+		h.t.Fatalf("failed to write '%s', err: %s", h.Path, err)
+	}
+}
+
+// Remove deletes the watched file.
+func (h *WatchHarness) Remove() {
+	h.t.Helper()
+	if err := os.Remove(h.Path); err != nil {
+		h.t.Fatalf("failed to remove '%s', err: %s", h.Path, err)
+	}
+}
+
+// Tick advances the clock by one interval, and waits until the poll
+// triggered by it is done.
+func (h *WatchHarness) Tick() {
+	h.Clock.BlockUntil(1)
+	h.Clock.Advance(h.interval)
+	h.Clock.BlockUntil(1)
+}
+
+// ExpectReload fails the test unless the next event is a reload.
+func (h *WatchHarness) ExpectReload() Event {
+	h.t.Helper()
+	ev := h.next()
+	if ev.Err != nil {
+		h.t.Fatalf("expected a reload, got error: %s", ev.Err)
+	}
+	return ev
+}
+
+// ExpectError fails the test unless the next event is an error.
+func (h *WatchHarness) ExpectError() error {
+	h.t.Helper()
+	ev := h.next()
+	if ev.Err == nil {
+		h.t.Fatalf("expected an error, got a reload")
+	}
+	return ev.Err
+}
+
+// ExpectNoEvent fails the test if any event is pending.
+func (h *WatchHarness) ExpectNoEvent() {
+	h.t.Helper()
+	select {
+	case ev := <-h.events:
+		h.t.Fatalf("unexpected event: %+v", ev)
+	default:
+	}
+}
+
+func (h *WatchHarness) next() Event {
+	h.t.Helper()
+	select {
+	case ev := <-h.events:
+		return ev
+	default:
+		h.t.Fatalf("no event delivered")
+	}
+	return Event{}
+}
@@ -0,0 +1,42 @@
+/**
+ * Unit test cases for WatchHarness
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 14:10:31
+ */
+
+package conftest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchHarness(t *testing.T) {
+	h := NewWatchHarness(t, "port: 80\n", time.Second)
+
+	h.Tick()
+	h.ExpectNoEvent()
+
+	h.Write("port: 8080\n")
+	h.Tick()
+	ev := h.ExpectReload()
+	if old, _ := ev.Old.GetInt("port"); old != 80 {
+		t.Errorf("old port, val: %d", old)
+	}
+	if cur, _ := ev.New.GetInt("port"); cur != 8080 {
+		t.Errorf("new port, val: %d", cur)
+	}
+
+	h.Write("port 9090\n")
+	h.Tick()
+	h.ExpectError()
+	if cur, _ := h.Watcher.Conf().GetInt("port"); cur != 8080 {
+		t.Errorf("last good conf should be kept, val: %d", cur)
+	}
+
+	h.Remove()
+	h.Tick()
+	h.ExpectError()
+	h.ExpectNoEvent()
+}
@@ -0,0 +1,61 @@
+/**
+ * Parsing by a context, so a slow config source, e.g. a network file
+ * system or a secret manager, respects the cancellation and deadline of
+ * startup.
+ *
+ *      e.g.
+ *          ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+ *          defer cancel()
+ *          err := conf.ParseContext(ctx)
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 16:03:52
+ */
+
+package goconf
+
+import (
+	"context"
+)
+
+const _CTX_CHECK_LINES = 1024 // lines read between checks of the context
+
+// ParseContext is Parse, which stops with the error of ctx once ctx is
+// done. Secret references are resolved by ctx if their resolvers are
+// ContextSecretResolvers.
+func (conf *Conf) ParseContext(ctx context.Context) error {
+	conf.ctx = ctx
+	defer func() { conf.ctx = nil }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return conf.parseFile(false)
+}
+
+// context returns the context of parsing, which is never done if conf isn't
+// parsed by ParseContext.
+func (conf *Conf) context() context.Context {
+	if conf.ctx == nil {
+		return context.Background()
+	}
+	return conf.ctx
+}
+
+// A ctxReader reads lines from r until ctx is done.
+type ctxReader struct {
+	r     lineReader
+	ctx   context.Context
+	lines int
+}
+
+func (cr *ctxReader) ReadString(delim byte) (string, error) {
+	if cr.lines%_CTX_CHECK_LINES == 0 {
+		if err := cr.ctx.Err(); err != nil {
+			return "", err
+		}
+	}
+	cr.lines++
+
+	return cr.r.ReadString(delim)
+}
@@ -0,0 +1,90 @@
+/**
+ * Defaults are a layer below the config file. An item missing from the
+ * file is read from the defaults, by getters and by Load.
+ *
+ *      e.g.
+ *          conf.SetDefaults(map[string]string{
+ *              "port":    "8080",
+ *              "db.host": "localhost",
+ *          })
+ *
+ *          conf.GetInt("port") => 8080, unless the file has 'port'
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:32:09
+ */
+
+package goconf
+
+import (
+	"errors"
+	"strings"
+)
+
+// SetDefaults sets the default items of conf, replacing the ones set
+// before. defaults is one of:
+//   - map[string]string of paths like 'port' or 'section.key' to values
+//   - map[string]interface{}, a tree of values as MergeMap reads
+//   - a pointer to a config object, whose fields are the defaults as
+//     FromStruct sets
+func (conf *Conf) SetDefaults(defaults interface{}) error {
+	layer := New("")
+	layer.opts = conf.opts
+
+	var err error
+	switch d := defaults.(type) {
+	case map[string]string:
+		tree := make(map[string]interface{})
+		for path, val := range d {
+			dot := strings.IndexByte(path, _PATH_SEP)
+			if dot <= 0 {
+				tree[path] = val
+				continue
+			}
+			members, ok := tree[path[:dot]].(map[string]interface{})
+			if !ok {
+				members = make(map[string]interface{})
+				tree[path[:dot]] = members
+			}
+			members[path[dot+1:]] = val
+		}
+		err = layer.MergeMap(tree)
+	case map[string]interface{}:
+		err = layer.MergeMap(d)
+	case nil:
+		return errors.New("nil defaults")
+	default:
+		err = layer.FromStruct(defaults)
+	}
+	if err != nil {
+		return err
+	}
+
+	conf.defaults = layer.sections
+	return nil
+}
+
+// lookupDefault finds 'key' in the defaults of section 'cur', or by path
+// 'section.key'.
+func (conf *Conf) lookupDefault(cur *section, key string) (*Item, bool) {
+	if item := conf.defaults[conf.sectionName(cur)].get(key); item != nil {
+		return item, true
+	}
+
+	dot := strings.IndexByte(key, _PATH_SEP)
+	if dot <= 0 {
+		return nil, false
+	}
+	item := conf.defaults[key[:dot]].get(key[dot+1:])
+	return item, item != nil
+}
+
+// section returns section 'name', or its defaults if the section is only
+// declared by SetDefaults.
+func (conf *Conf) section(name string) (*section, bool) {
+	if sec, ok := conf.sections[name]; ok {
+		return sec, true
+	}
+	sec, ok := conf.defaults[name]
+	return sec, ok
+}
@@ -0,0 +1,90 @@
+/**
+ * Diff of two confs, e.g. to log what a reload changed:
+ *
+ *      e.g.
+ *          for _, c := range goconf.Diff(old, new) {
+ *              log.Print(c.String())
+ *          }
+ *          > db.port: 3306 => 3307
+ *          > + cache.size: 10
+ *          > - debug: true
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 15:02:17
+ */
+
+package goconf
+
+import (
+	"fmt"
+)
+
+// Diff returns the changes from the items of a to the items of b: OpSet for
+// an added or changed item, and OpDelete for a removed one, with the old
+// values. They're sorted by section, the global section first, and then by
+// key. So ApplyPatch of the changes on a gets the items of b, unless a value
+// has several lines.
+func Diff(a, b *Conf) []Change {
+	a.loadSections()
+	b.loadSections()
+
+	var changes []Change
+	for _, name := range diffSections(a, b) {
+		secA, secB := a.sections[name], b.sections[name]
+		section := name
+		if name == _GLOBAL {
+			section = ""
+		}
+
+		for _, key := range secA.sortedKeys() {
+			itemA := secA.get(key)
+			if itemB := secB.get(key); itemB == nil {
+				changes = append(changes, Change{Op: OpDelete, Section: section, Key: key, OldValue: itemA.val})
+			} else if itemB.val != itemA.val {
+				changes = append(changes, Change{Op: OpSet, Section: section, Key: key, Value: itemB.val,
+					OldValue: itemA.val})
+			}
+		}
+		for _, key := range secB.sortedKeys() {
+			if secA.get(key) == nil {
+				changes = append(changes, Change{Op: OpSet, Section: section, Key: key, Value: secB.get(key).val})
+			}
+		}
+	}
+
+	return changes
+}
+
+// diffSections returns the names of the sections of a or b, the global
+// section first.
+func diffSections(a, b *Conf) []string {
+	names := map[string]bool{}
+	for _, conf := range []*Conf{a, b} {
+		for _, name := range conf.Sections(false) {
+			names[name] = true
+		}
+	}
+
+	return append([]string{_GLOBAL}, sortedKeys(names)...)
+}
+
+// String describes the change as a line of a log. An item is named by
+// 'section.key', and a change made by Diff shows its old value.
+func (c *Change) String() string {
+	path := itemPath(c.sectionName(), c.Key)
+	switch c.Op {
+	case OpSet:
+		if c.OldValue == "" {
+			return fmt.Sprintf("+ %s: %s", path, c.Value)
+		}
+		return fmt.Sprintf("%s: %s => %s", path, c.OldValue, c.Value)
+	case OpDelete:
+		if c.OldValue == "" {
+			return "- " + path
+		}
+		return fmt.Sprintf("- %s: %s", path, c.OldValue)
+	case OpRename:
+		return fmt.Sprintf("%s => %s", path, itemPath(c.sectionName(), c.NewKey))
+	}
+	return fmt.Sprintf("%s %s", c.Op, path)
+}
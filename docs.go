@@ -0,0 +1,101 @@
+/**
+ * WriteDocs writes a reference of the items of a config struct, so docs
+ * for operators are generated from the source. Each item has its key,
+ * type, default and description, from the tags as Scaffold reads them.
+ *
+ *      e.g. WriteDocs(&Config{}, os.Stdout, DocMarkdown) writes:
+ *          | Key | Type | Default | Description |
+ *          | --- | --- | --- | --- |
+ *          | port | int | 8080 | port to listen on |
+ *          | db.user | string | app |  |
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 12:14:36
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// A DocFormat is the format of docs written by WriteDocs.
+type DocFormat int
+
+const (
+	DocMarkdown DocFormat = iota // a Markdown table
+	DocText                      // a plain-text table with aligned columns
+)
+
+var docHeader = []string{"Key", "Type", "Default", "Description"}
+
+// WriteDocs writes the reference of the items of a config object to w in
+// format. Items of sections are named by paths 'section.key'. Types are the
+// ones of ItemSchema.Type, or 'any' for fields of other types.
+func WriteDocs(configObjPtr interface{}, w io.Writer, format DocFormat) error {
+	docs, err := fieldDocs(configObjPtr)
+	if err != nil {
+		return err
+	}
+
+	rows := [][]string{docHeader}
+	for _, doc := range docs {
+		typ := doc.typ
+		if typ == "" {
+			typ = "any"
+		}
+		if doc.array {
+			typ = "array of " + typ
+		}
+		if doc.optional {
+			typ += ", optional"
+		}
+		path := doc.key
+		if doc.section != "" {
+			path = doc.section + string(_PATH_SEP) + doc.key
+		}
+		rows = append(rows, []string{path, typ, doc.def, doc.desc})
+	}
+
+	buf := bufio.NewWriter(w)
+	if format == DocMarkdown {
+		writeMarkdownTable(buf, rows)
+	} else {
+		writeTextTable(buf, rows)
+	}
+
+	return buf.Flush()
+}
+
+func writeMarkdownTable(buf *bufio.Writer, rows [][]string) {
+	for idx, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.ReplaceAll(cell, "|", "\\|")
+		}
+		buf.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if idx == 0 {
+			buf.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+		}
+	}
+}
+
+func writeTextTable(buf *bufio.Writer, rows [][]string) {
+	widths := make([]int, len(docHeader))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	for _, row := range rows {
+		var line string
+		for i, cell := range row {
+			line += cell + pad(cell, widths[i]) + "  "
+		}
+		buf.WriteString(strings.TrimRight(line, _SPACE_CHARS) + "\n")
+	}
+}
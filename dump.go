@@ -0,0 +1,87 @@
+/**
+ * DumpGo compiles a config into Go source, so a small static config can be
+ * built into a binary while it's still edited as a config file, e.g. by
+ * 'go generate'. The config is declared as a map of values, which is the
+ * layout read by MergeMap.
+ *
+ *      e.g. config file:
+ *          > name: app
+ *          > [db]
+ *          > port: 3306
+ *
+ *      DumpGo(conf, "config", "Defaults") generates:
+ *          package config
+ *
+ *          var Defaults = map[string]interface{}{
+ *              "name": "app",
+ *              "db": map[string]interface{}{
+ *                  "port": "3306",
+ *              },
+ *          }
+ *
+ *      which is parsed back by conf.MergeMap(config.Defaults). Values are
+ *  kept as strings, arrays included, so every value is read back as written.
+ *  An array with its own separator keeps its key, e.g. '"[@hosts@,]": "a,b"',
+ *  and the others are split by the element separator of the conf merging the
+ *  map.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 00:08:31
+ */
+
+package goconf
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/chosen0ne/goutils"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DumpGo returns the source of a Go file in package pkg, which declares
+// variable varName holding the items of conf. Values are kept as strings.
+func DumpGo(conf *Conf, pkg, varName string) ([]byte, error) {
+	if !token.IsIdentifier(pkg) || !token.IsIdentifier(varName) {
+		return nil, goutils.NewErr("bad package '%s' or variable '%s'", pkg, varName)
+	}
+
+	if err := conf.loadSections(); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.Buffer{}
+	fmt.Fprintf(&buf, "// Code generated by goconf.DumpGo; DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(&buf, "var %s = map[string]interface{}{\n", varName)
+	dumpItems(&buf, conf.sections[_GLOBAL])
+	for _, name := range conf.Sections(false) {
+		if conf.sections[_GLOBAL].get(name) != nil {
+			return nil, goutils.NewErr("section '%s' conflicts with a global item", name)
+		}
+		fmt.Fprintf(&buf, "%s: map[string]interface{}{\n", strconv.Quote(name))
+		dumpItems(&buf, conf.sections[name])
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+	return src, nil
+}
+
+func dumpItems(buf *bytes.Buffer, s *section) {
+	items := s.items()
+	sort.Slice(items, func(i, j int) bool { return items[i].key < items[j].key })
+	for _, item := range items {
+		key := item.key
+		if sep := item.elementSep(); sep != _DEFAULT_SEP && strings.IndexByte(item.val, sep) >= 0 {
+			key = _ARRAY_LEFT + key + string(_ARRAY_SEP_TAG) + string(sep) + string(_SECTION_RIGHT)
+		}
+		fmt.Fprintf(buf, "%s: %s,\n", strconv.Quote(key), strconv.Quote(item.val))
+	}
+}
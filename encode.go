@@ -0,0 +1,296 @@
+/**
+ * FromStruct sets a conf by a config object, the reverse of LoadFromConf.
+ * So defaults can be declared in Go code, and edited configs saved back.
+ *
+ *      e.g.
+ *          type Config struct {
+ *              Name string
+ *              DB   struct {
+ *                  Port int
+ *              }
+ *          }
+ *
+ *          conf.FromStruct(&Config{Name: "app"}) sets:
+ *          > name: app
+ *          > [db]
+ *          > port: 0
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 09:40:12
+ */
+
+package goconf
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromStruct sets the items and sections of conf by the fields of the
+// config object, which are mapped as Load does. A field overrides the
+// item it's loaded from, and a new item is named like 'a_example_field'.
+// Struct fields are sections, and maps of structs are sections named
+// 'prefix:name'. Empty strings, empty slices and nil pointers are skipped.
+func (conf *Conf) FromStruct(configObjPtr interface{}) error {
+	v := reflect.ValueOf(configObjPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("configObj must be a pointer to a struct")
+	}
+	if err := conf.loadSections(); err != nil {
+		return err
+	}
+
+	m, err := conf.structMap(v.Elem(), conf.sections[_GLOBAL], true)
+	if err != nil {
+		return err
+	}
+
+	return conf.MergeMap(m)
+}
+
+// structMap returns the fields of struct v as a tree of values read by
+// MergeMap. Existing items of sec keep their keys. Sections are only
+// declared by the fields of the top struct.
+func (conf *Conf) structMap(v reflect.Value, sec *section, top bool) (map[string]interface{}, error) {
+	has := func(name string) bool {
+		_, ok := conf.lookup(sec, name)
+		return ok || (top && conf.HasSection(name))
+	}
+
+	m := make(map[string]interface{})
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name, err := parseConfigOptName(field.Name, has)
+		if err != nil {
+			name = keyName(field.Name)
+		}
+
+		fv := v.Field(i)
+		if isSectionMap(fv.Type()) {
+			if !top {
+				return nil, errors.New("sections aren't nested, field: " + field.Name)
+			}
+			if err := conf.sectionMapValues(m, &field, fv); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isSectionStruct(fv.Type()) {
+			if !top {
+				return nil, errors.New("sections aren't nested, field: " + field.Name)
+			}
+			members, err := conf.structMap(fv, conf.sections[name], false)
+			if err != nil {
+				return nil, err
+			}
+			m[name] = members
+			continue
+		}
+
+		val, err := conf.fieldValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %s", field.Name, err)
+		}
+		if val != nil {
+			m[name] = val
+		}
+	}
+
+	return m, nil
+}
+
+// sectionMapValues puts a section 'prefix:name' into m for every member of
+// a map of structs.
+func (conf *Conf) sectionMapValues(m map[string]interface{}, field *reflect.StructField, fv reflect.Value) error {
+	prefix := sectionPrefix(field)
+	for _, p := range sectionPrefixes(field) {
+		if conf.hasSectionPrefix(p) {
+			prefix = p
+			break
+		}
+	}
+
+	iter := fv.MapRange()
+	for iter.Next() {
+		ele := iter.Value()
+		if ele.Kind() == reflect.Ptr {
+			if ele.IsNil() {
+				continue
+			}
+			ele = ele.Elem()
+		}
+
+		name := prefix + string(_SECTION_PREFIX_SEP) + iter.Key().String()
+		members, err := conf.structMap(ele, conf.sections[name], false)
+		if err != nil {
+			return err
+		}
+		m[name] = members
+	}
+
+	return nil
+}
+
+func (conf *Conf) hasSectionPrefix(prefix string) bool {
+	for name := range conf.sections {
+		if strings.HasPrefix(name, prefix+string(_SECTION_PREFIX_SEP)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSectionStruct reports whether a field of type t is a section, as
+// loadField does.
+func isSectionStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && converterOf(t) == nil &&
+		!t.Implements(textMarshalerType) && !reflect.PtrTo(t).Implements(unmarshalerType)
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// fieldValue returns the value of a field as a string, or a slice of
+// strings for arrays. It's nil if the field should be skipped.
+func (conf *Conf) fieldValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		if !v.Type().Implements(textMarshalerType) && !v.Type().Implements(stringerType) {
+			return conf.fieldValue(v.Elem())
+		}
+	}
+
+	var s string
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		s = string(text)
+	} else if str, ok := stringer(v); ok {
+		s = str.String()
+	} else if v.Type() == durationType {
+		// loaded by its converter, e.g. '1m30s'
+		s = time.Duration(v.Int()).String()
+	} else {
+		switch kind := v.Kind(); {
+		case kind == reflect.String:
+			s = v.String()
+		case kind == reflect.Bool:
+			s = strconv.FormatBool(v.Bool())
+		case isUint(kind):
+			s = strconv.FormatUint(v.Uint(), 10)
+		case isInt(kind):
+			s = strconv.FormatInt(v.Int(), 10)
+		case kind == reflect.Float32 || kind == reflect.Float64:
+			s = strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+		case kind == reflect.Slice:
+			return conf.sliceValue(v)
+		case kind == reflect.Map && v.Type().Key().Kind() == reflect.String:
+			return conf.mapValue(v)
+		default:
+			return nil, errors.New("not support type: " + v.Type().String())
+		}
+	}
+
+	if s == "" {
+		return nil, nil
+	}
+	return s, nil
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// stringer returns v as a fmt.Stringer if it's a struct or a pointer, e.g.
+// *url.URL. Scalars keep their values, as an enum is loaded from a number.
+func stringer(v reflect.Value) (fmt.Stringer, bool) {
+	if v.Kind() == reflect.Struct && v.CanAddr() {
+		v = v.Addr()
+	}
+	if v.Kind() != reflect.Ptr || !v.Type().Implements(stringerType) {
+		return nil, false
+	}
+	return v.Interface().(fmt.Stringer), true
+}
+
+// sliceValue returns the elements of a slice, which are joined by MergeMap.
+// A []byte is encoded by base64, and a matrix is matrixRows.
+func (conf *Conf) sliceValue(v reflect.Value) (interface{}, error) {
+	if v.Type().Elem().Kind() == reflect.Uint8 {
+		if v.Len() == 0 {
+			return nil, nil
+		}
+		return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+	}
+
+	eles := make([]interface{}, 0, v.Len())
+	var rows matrixRows
+	for i := 0; i < v.Len(); i++ {
+		ele, err := conf.fieldValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		if row, ok := ele.([]interface{}); ok {
+			cols := make([]string, len(row))
+			for j, col := range row {
+				s, ok := col.(string)
+				if !ok {
+					return nil, errors.New("not support type: " + v.Type().String())
+				} else if strings.ContainsAny(s, _SPACE_CHARS+string(_MATRIX_ROW_SEP)) {
+					return nil, fmt.Errorf("element '%s' of a matrix contains a separator", s)
+				}
+				cols[j] = s
+			}
+			rows = append(rows, strings.Join(cols, " "))
+		} else if ele != nil {
+			eles = append(eles, ele)
+		}
+	}
+
+	if len(rows) != 0 {
+		return rows, nil
+	} else if len(eles) == 0 {
+		return nil, nil
+	}
+	return eles, nil
+}
+
+// mapValue returns a map with string keys as an inline map, by the
+// separators of conf.
+func (conf *Conf) mapValue(v reflect.Value) (interface{}, error) {
+	entrySep, pairSep := conf.mapSeparators()
+	var entries []string
+	iter := v.MapRange()
+	for iter.Next() {
+		val, err := conf.fieldValue(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := val.([]interface{}); ok {
+			return nil, errors.New("not support value type for map: " + v.Type().Elem().String())
+		}
+		s, _ := val.(string)
+		entries = append(entries, iter.Key().String()+pairSep+s)
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, entrySep), nil
+}
@@ -0,0 +1,142 @@
+/**
+ * Encrypted values keep secrets out of config files in plaintext. A value
+ * 'ENC(SCHEME:CIPHERTEXT)' is decrypted while parsing by the Decryptor given
+ * by WithDecryptor, so Get* and Load see the plaintext.
+ *
+ *      e.g. config file:
+ *          > password: ENC(AES256:Zm9vYmFy...)
+ *
+ *          conf := New("app.conf", WithDecryptor(AESDecryptor(key)))
+ *
+ *  AESDecryptor decrypts the scheme 'AES256', which is AES-256-GCM of the
+ *  base64 of nonce and ciphertext, as EncryptAES produces. Other schemes,
+ *  e.g. of a KMS, are handled by custom decryptors. Without a decryptor,
+ *  encrypted values are kept as they are.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 04:25:33
+ */
+
+package goconf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/chosen0ne/goutils"
+	"strings"
+)
+
+const (
+	_ENC_PREFIX = "ENC("
+	_ENC_SUFFIX = ")"
+	_ENC_SEP    = ":"
+	_AES_SCHEME = "AES256"
+)
+
+// A Decryptor decrypts the ciphertext of an encrypted value by scheme, e.g.
+// "AES256".
+type Decryptor interface {
+	Decrypt(scheme, ciphertext string) (string, error)
+}
+
+// DecryptorFunc adapts a function to a Decryptor.
+type DecryptorFunc func(scheme, ciphertext string) (string, error)
+
+func (f DecryptorFunc) Decrypt(scheme, ciphertext string) (string, error) {
+	return f(scheme, ciphertext)
+}
+
+// isEncrypted reports whether val is 'ENC(...)'.
+func isEncrypted(val string) bool {
+	return strings.HasPrefix(val, _ENC_PREFIX) && strings.HasSuffix(val, _ENC_SUFFIX)
+}
+
+// decrypt replaces encrypted values of the items by their plaintexts.
+func (conf *Conf) decrypt(d Decryptor) error {
+	return conf.Walk(func(section string, item *Item) error {
+		if !isEncrypted(item.val) {
+			return nil
+		}
+
+		enc := item.val[len(_ENC_PREFIX) : len(item.val)-len(_ENC_SUFFIX)]
+		sep := strings.Index(enc, _ENC_SEP)
+		if sep <= 0 {
+			return &ParseError{Code: E_DECRYPT, File: conf.filePath,
+				Msg: fmt.Sprintf("'%s': no scheme in encrypted value", itemPath(section, item.key))}
+		}
+
+		plain, err := d.Decrypt(enc[:sep], enc[sep+1:])
+		if err != nil {
+			return &ParseError{Code: E_DECRYPT, File: conf.filePath,
+				Msg: fmt.Sprintf("failed to decrypt '%s': %s", itemPath(section, item.key), err), Err: err}
+		}
+		item.raw = item.Raw()
+		item.val = plain
+		item.origin.secret = true
+		return nil
+	})
+}
+
+// AESDecryptor decrypts scheme 'AES256' by a 32-byte key.
+func AESDecryptor(key []byte) Decryptor {
+	return DecryptorFunc(func(scheme, ciphertext string) (string, error) {
+		if scheme != _AES_SCHEME {
+			return "", goutils.NewErr("not supported scheme '%s'", scheme)
+		}
+
+		gcm, err := newGCM(key)
+		if err != nil {
+			return "", err
+		}
+		data, err := base64.StdEncoding.DecodeString(ciphertext)
+		if err != nil {
+			return "", goutils.WrapErr(err)
+		}
+		if len(data) < gcm.NonceSize() {
+			return "", errors.New("ciphertext too short")
+		}
+
+		plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+		if err != nil {
+			return "", goutils.WrapErr(err)
+		}
+		return string(plain), nil
+	})
+}
+
+// EncryptAES encrypts plaintext by a 32-byte key into a value
+// 'ENC(AES256:...)', which AESDecryptor decrypts.
+func EncryptAES(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", goutils.WrapErr(err)
+	}
+	data := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return _ENC_PREFIX + _AES_SCHEME + _ENC_SEP + base64.StdEncoding.EncodeToString(data) + _ENC_SUFFIX, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, goutils.NewErr("AES256 needs a 32-byte key, got %d bytes", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,116 @@
+/**
+ * Environment variables override the items parsed from the config file, so
+ * containers can change a value without editing the mounted file. The
+ * variable of an item is named by the prefix, the section and the key in
+ * upper case, joined by '_'. Other chars than letters and digits become '_'.
+ *
+ *      e.g. with WithEnvOverrides("GOCONF"):
+ *          > name: app             <= GOCONF_NAME
+ *          > [server]
+ *          > port: 8080            <= GOCONF_SERVER_PORT
+ *          > read-timeout: 3s      <= GOCONF_SERVER_READ_TIMEOUT
+ *
+ * Only items in the file are overridden, and empty variables are ignored.
+ * A variable may name several items, e.g. GOCONF_DB_HOST names the global
+ * 'db_host' and 'host' in section db. It overrides the item of the longest
+ * section name, i.e. items of sections win over global items, and the
+ * others get warnings.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 23:36:50
+ */
+
+package goconf
+
+import (
+	"os"
+	"strings"
+)
+
+// _ENV_PREFIX is the prefix of variables if WithEnvOverrides is given "".
+const _ENV_PREFIX = "GOCONF"
+
+// OverrideFromEnv sets the items which have environment variables named
+// with prefix, see WithEnvOverrides. It returns the names of the variables
+// used.
+func (conf *Conf) OverrideFromEnv(prefix string) []string {
+	if prefix == "" {
+		prefix = _ENV_PREFIX
+	}
+
+	var names []string
+	targets := make(map[string][]envTarget)
+	conf.Walk(func(section string, item *Item) error {
+		name := envName(prefix, section, item.key)
+		if _, ok := targets[name]; !ok {
+			names = append(names, name)
+		}
+		targets[name] = append(targets[name], envTarget{section, item})
+		return nil
+	})
+
+	var used []string
+	for _, name := range names {
+		val := strings.Trim(os.Getenv(name), _SPACE_CHARS)
+		if val == "" {
+			continue
+		}
+
+		ts := targets[name]
+		t := ts[0]
+		for _, other := range ts[1:] {
+			if other.sectionLen() > t.sectionLen() {
+				t = other
+			}
+		}
+		for _, other := range ts {
+			if other != t {
+				conf.warn(other.item.origin, other.path(), "env "+name+" is ambiguous, it overrides '"+t.path()+"'")
+			}
+		}
+
+		t.item.val, t.item.raw = val, ""
+		t.item.origin = origin{env: name}
+		conf.logf("'%s' is overridden by env %s", t.path(), name)
+		used = append(used, name)
+	}
+
+	return used
+}
+
+// An envTarget is an item named by an env variable.
+type envTarget struct {
+	section string
+	item    *Item
+}
+
+func (t envTarget) path() string {
+	return itemPath(t.section, t.item.key)
+}
+
+// sectionLen is the length of the section name, 0 for the global section.
+func (t envTarget) sectionLen() int {
+	if t.section == _GLOBAL {
+		return 0
+	}
+	return len(t.section)
+}
+
+// envName returns the variable of item 'key' in section 'section'.
+func envName(prefix, section, key string) string {
+	name := prefix + "_"
+	if section != _GLOBAL {
+		name += section + "_"
+	}
+	name += key
+
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -0,0 +1,290 @@
+/**
+ * Errors returned by goconf can be inspected by errors.Is and errors.As:
+ *
+ *      e.g.
+ *          port, err := conf.GetInt("port")
+ *          if errors.Is(err, goconf.ErrKeyNotFound) {
+ *              port = 8080
+ *          } else if err != nil {
+ *              // present but malformed, errors.Is(err, goconf.ErrTypeMismatch)
+ *          }
+ *
+ *          var perr *goconf.ParseError
+ *          if errors.As(err, &perr) {
+ *              // perr.File, perr.Line
+ *          }
+ *
+ *  Every error type has a stable machine-readable code, so tooling and tests
+ *  can assert a failure class instead of matching messages:
+ *
+ *          if goconf.ErrorCode(err) == goconf.E_DUP_SECTION {
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 15:20:13
+ */
+
+package goconf
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// A Code classifies an error. Codes are stable across releases.
+type Code string
+
+const (
+	E_PARSE_NO_SEP      Code = "E_PARSE_NO_SEP"      // a line without ':'
+	E_PARSE_EMPTY_VALUE Code = "E_PARSE_EMPTY_VALUE" // an item without value
+	E_PARSE_HEREDOC     Code = "E_PARSE_HEREDOC"     // a multi-line value without its end
+	E_PARSE_ARRAY       Code = "E_PARSE_ARRAY"       // a malformed '[@key@sep]'
+	E_PARSE_CONDITION   Code = "E_PARSE_CONDITION"   // a malformed condition of '[if ...]'
+	E_PARSE_PARENT      Code = "E_PARSE_PARENT"      // a parent of '[child : parent]' not declared before
+	E_DUP_SECTION       Code = "E_DUP_SECTION"       // a section declared twice
+	E_DUP_KEY           Code = "E_DUP_KEY"           // a key repeated, by DuplicateError
+	E_DIRECTIVE         Code = "E_DIRECTIVE"         // a malformed or unknown directive
+	E_INCLUDE           Code = "E_INCLUDE"           // an included file can't be read
+	E_JSON              Code = "E_JSON"              // a malformed JSON config file
+	E_FORMAT            Code = "E_FORMAT"            // a malformed file of a registered format
+	E_FORMAT_VALUE      Code = "E_FORMAT_VALUE"      // a value of JSON, YAML... which can't be an item
+	E_MERGE             Code = "E_MERGE"             // values which can't be merged by the strategy
+	E_DECRYPT           Code = "E_DECRYPT"           // an encrypted value which can't be decrypted
+	E_SECRET            Code = "E_SECRET"            // a secret reference which can't be resolved
+	E_INTERPOLATE       Code = "E_INTERPOLATE"       // a '${name:arg}' which can't be interpolated
+	E_LIMIT             Code = "E_LIMIT"             // an input exceeding the limits of WithLimits
+
+	E_TYPE_INT         Code = "E_TYPE_INT"
+	E_TYPE_UINT        Code = "E_TYPE_UINT"
+	E_TYPE_FLOAT       Code = "E_TYPE_FLOAT"
+	E_TYPE_BOOL        Code = "E_TYPE_BOOL"
+	E_TYPE_INT_ARRAY   Code = "E_TYPE_INT_ARRAY"
+	E_TYPE_FLOAT_ARRAY Code = "E_TYPE_FLOAT_ARRAY"
+	E_TYPE_BOOL_ARRAY  Code = "E_TYPE_BOOL_ARRAY"
+	E_TYPE             Code = "E_TYPE" // other type mismatches
+
+	E_KEY_NOT_FOUND     Code = "E_KEY_NOT_FOUND"
+	E_SECTION_NOT_FOUND Code = "E_SECTION_NOT_FOUND"
+
+	E_FILE_NOT_FOUND  Code = "E_FILE_NOT_FOUND"
+	E_FILE_PERMISSION Code = "E_FILE_PERMISSION"
+	E_FILE_IS_DIR     Code = "E_FILE_IS_DIR"
+	E_FILE            Code = "E_FILE" // other failures to read a config file
+
+	E_UNKNOWN_KEYS   Code = "E_UNKNOWN_KEYS"
+	E_MISSING_FIELDS Code = "E_MISSING_FIELDS"
+)
+
+// A CodedError is an error with a Code. All the error types of goconf
+// implement it.
+type CodedError interface {
+	error
+	ErrorCode() Code
+}
+
+// ErrorCode returns the code of the first CodedError in the chain of err,
+// or an empty code if there's none.
+func ErrorCode(err error) Code {
+	var cerr CodedError
+	if errors.As(err, &cerr) {
+		return cerr.ErrorCode()
+	}
+	return ""
+}
+
+var (
+	ErrKeyNotFound     = errors.New("non-exist item")
+	ErrSectionNotFound = errors.New("no section")
+	ErrTypeMismatch    = errors.New("type mismatch")
+
+	// The config file can't be read
+	ErrFileNotFound = errors.New("config file not found")
+	ErrPermission   = errors.New("permission denied to config file")
+	ErrIsDirectory  = errors.New("config file is a directory")
+)
+
+// A ParseError is a syntax error at a line of a config file.
+type ParseError struct {
+	Code Code
+	File string // empty if not parsed from a file
+	Line int    // 0 if unknown
+	Msg  string
+	Err  error // underlying error, if any
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		if e.File == "" {
+			return e.Msg
+		}
+		return fmt.Sprintf("%s: %s", e.File, e.Msg)
+	}
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParseError) ErrorCode() Code {
+	return e.Code
+}
+
+// A TypeError reports a value which can't be converted to the requested
+// type. It matches ErrTypeMismatch.
+type TypeError struct {
+	Key  string
+	Val  string
+	Type string // requested type, e.g. "int"
+	Err  error  // conversion error, if any
+}
+
+func (e *TypeError) Error() string {
+	return message(MsgTypeMismatch, e.Key, e.Val, e.Type)
+}
+
+func (e *TypeError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+func (e *TypeError) Unwrap() error {
+	return e.Err
+}
+
+var typeCodes = map[string]Code{
+	"int":         E_TYPE_INT,
+	"uint":        E_TYPE_UINT,
+	"float":       E_TYPE_FLOAT,
+	"bool":        E_TYPE_BOOL,
+	"int array":   E_TYPE_INT_ARRAY,
+	"float array": E_TYPE_FLOAT_ARRAY,
+	"bool array":  E_TYPE_BOOL_ARRAY,
+}
+
+func (e *TypeError) ErrorCode() Code {
+	if code, ok := typeCodes[e.Type]; ok {
+		return code
+	}
+	return E_TYPE
+}
+
+// A NotFoundError reports a missing item or section. It matches its Kind,
+// ErrKeyNotFound or ErrSectionNotFound.
+type NotFoundError struct {
+	Kind error
+	Name string // key, 'section.key' or section name
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Kind == ErrSectionNotFound {
+		return message(MsgSectionNotFound, e.Name)
+	}
+	return message(MsgKeyNotFound, e.Name)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *NotFoundError) ErrorCode() Code {
+	if e.Kind == ErrSectionNotFound {
+		return E_SECTION_NOT_FOUND
+	}
+	return E_KEY_NOT_FOUND
+}
+
+// A FileError reports a config file which can't be opened or read. It
+// matches its Kind, one of ErrFileNotFound, ErrPermission and ErrIsDirectory,
+// or nil for other failures.
+type FileError struct {
+	Path string
+	Kind error
+	Err  error // error from os, if any
+}
+
+func (e *FileError) Error() string {
+	switch e.Kind {
+	case ErrFileNotFound:
+		return message(MsgFileNotFound, e.Path)
+	case ErrPermission:
+		return message(MsgFilePermission, e.Path)
+	case ErrIsDirectory:
+		return message(MsgFileIsDirectory, e.Path)
+	}
+
+	return message(MsgFileUnreadable, e.Path, e.Err)
+}
+
+func (e *FileError) Is(target error) bool {
+	return e.Kind != nil && target == e.Kind
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+func (e *FileError) ErrorCode() Code {
+	switch e.Kind {
+	case ErrFileNotFound:
+		return E_FILE_NOT_FOUND
+	case ErrPermission:
+		return E_FILE_PERMISSION
+	case ErrIsDirectory:
+		return E_FILE_IS_DIR
+	}
+	return E_FILE
+}
+
+// fileErr classifies an error from opening or reading file 'path'.
+func fileErr(path string, err error) error {
+	e := &FileError{Path: path, Err: err}
+	if errors.Is(err, fs.ErrNotExist) {
+		e.Kind = ErrFileNotFound
+	} else if errors.Is(err, fs.ErrPermission) {
+		e.Kind = ErrPermission
+	}
+
+	return e
+}
+
+// An UnknownKeysError lists the config items which no field of the config
+// object consumed, which are usually typos.
+type UnknownKeysError struct {
+	Keys []string
+}
+
+func (e *UnknownKeysError) Error() string {
+	return message(MsgUnknownKeys, strings.Join(e.Keys, ", "))
+}
+
+func (e *UnknownKeysError) ErrorCode() Code {
+	return E_UNKNOWN_KEYS
+}
+
+// A MissingFieldsError lists the fields of the config object which no
+// config item matched, e.g. 'Section1.Port'.
+type MissingFieldsError struct {
+	Fields []string
+}
+
+func (e *MissingFieldsError) Error() string {
+	return message(MsgMissingFields, strings.Join(e.Fields, ", "))
+}
+
+func (e *MissingFieldsError) ErrorCode() Code {
+	return E_MISSING_FIELDS
+}
+
+func keyNotFound(key string) error {
+	return &NotFoundError{ErrKeyNotFound, key}
+}
+
+func sectionNotFound(name string) error {
+	return &NotFoundError{ErrSectionNotFound, name}
+}
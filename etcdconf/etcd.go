@@ -0,0 +1,191 @@
+/**
+ * Package etcdconf reads a config from the keys of etcd under a prefix, so
+ * service configs stored centrally don't need to be synced to files. A key
+ * right under the prefix is a global item, and a key one level deeper is an
+ * item of the section named by that level.
+ *
+ *      e.g. keys under prefix '/app/':
+ *          /app/name           app
+ *          /app/db/host        localhost
+ *          /app/db/port        3306
+ *
+ *      is the same as config file:
+ *          > name: app
+ *          > [db]
+ *          > host: localhost
+ *          > port: 3306
+ *
+ *  Deeper keys keep the rest of their path as the item key, e.g. 'pool/size'
+ *  for '/app/db/pool/size'.
+ *
+ *      e.g.
+ *          cli, err := clientv3.New(clientv3.Config{Endpoints: endpoints})
+ *          conf, err := etcdconf.Load(ctx, cli, "/app/")
+ *
+ *          go etcdconf.Watch(ctx, cli, "/app/", func(conf *goconf.Conf, err error) {
+ *              // use the reloaded conf
+ *          })
+ *
+ *  A whole config file stored in a key is provided by KeyProvider, whose
+ *  format is detected by the extension of the key, e.g. '/app/config.yaml'.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 01:48:20
+ */
+
+package etcdconf
+
+import (
+	"context"
+	"github.com/chosen0ne/goconf"
+	"go.etcd.io/etcd/client/v3"
+	"strings"
+	"sync"
+)
+
+// Client is the part of *clientv3.Client used by etcdconf.
+type Client interface {
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+const _PATH_SEP = "/"
+
+// Load reads the keys under prefix into a new Conf, which is finished as a
+// parsed file is, e.g. by env overrides and secret resolvers. The options
+// are the ones of goconf.New. A prefix without a trailing '/' has one
+// appended, so '/app' doesn't read '/application/x'.
+func Load(ctx context.Context, cli Client, prefix string, opts ...goconf.Option) (*goconf.Conf, error) {
+	prefix = dirPrefix(prefix)
+	resp, err := cli.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	for _, kv := range resp.Kvs {
+		path := strings.Trim(strings.TrimPrefix(string(kv.Key), prefix), _PATH_SEP)
+		if path == "" {
+			continue
+		}
+
+		parts := strings.SplitN(path, _PATH_SEP, 2)
+		if len(parts) == 1 {
+			if _, ok := m[path].(map[string]interface{}); ok {
+				return nil, conflictErr(prefix, path)
+			}
+			m[path] = string(kv.Value)
+			continue
+		}
+
+		members, ok := m[parts[0]].(map[string]interface{})
+		if !ok {
+			if _, exist := m[parts[0]]; exist {
+				return nil, conflictErr(prefix, parts[0])
+			}
+			members = make(map[string]interface{})
+			m[parts[0]] = members
+		}
+		members[parts[1]] = string(kv.Value)
+	}
+
+	return goconf.ParseMapContext(ctx, m, opts...)
+}
+
+// dirPrefix returns prefix ending with '/', unless it's empty.
+func dirPrefix(prefix string) string {
+	if prefix != "" && !strings.HasSuffix(prefix, _PATH_SEP) {
+		return prefix + _PATH_SEP
+	}
+	return prefix
+}
+
+func conflictErr(prefix, name string) error {
+	return &goconf.ParseError{
+		Code: goconf.E_FORMAT_VALUE,
+		File: prefix,
+		Msg:  "'" + name + "' is both an item and a section",
+	}
+}
+
+// Watch watches the keys under prefix, and calls fn with the conf reloaded
+// after every change, or with the error of reloading. It blocks until ctx
+// is done or the watch is closed by etcd, and returns the cause.
+func Watch(
+	ctx context.Context,
+	cli Client,
+	prefix string,
+	fn func(conf *goconf.Conf, err error),
+	opts ...goconf.Option) error {
+	prefix = dirPrefix(prefix)
+	for resp := range cli.Watch(ctx, prefix, clientv3.WithPrefix()) {
+		if err := resp.Err(); err != nil {
+			return err
+		}
+		if resp.Canceled {
+			break
+		}
+
+		fn(Load(ctx, cli, prefix, opts...))
+	}
+
+	return ctx.Err()
+}
+
+// A KeyProvider is a goconf.Provider of the value of an etcd key.
+type KeyProvider struct {
+	cli    Client
+	key    string
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+func NewKeyProvider(cli Client, key string) *KeyProvider {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &KeyProvider{cli: cli, key: key, ctx: ctx, cancel: cancel}
+}
+
+func (p *KeyProvider) Path() string {
+	return p.key
+}
+
+func (p *KeyProvider) Read() ([]byte, error) {
+	return p.ReadContext(p.ctx)
+}
+
+// ReadContext reads the key by ctx, so a slow etcd doesn't block startup
+// after the deadline of ctx.
+func (p *KeyProvider) ReadContext(ctx context.Context) ([]byte, error) {
+	resp, err := p.cli.Get(ctx, p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, &goconf.FileError{Path: p.key, Kind: goconf.ErrFileNotFound}
+	}
+
+	return resp.Kvs[0].Value, nil
+}
+
+// Watch watches the key in a goroutine until Close.
+func (p *KeyProvider) Watch(changed chan<- struct{}) {
+	p.once.Do(func() {
+		go func() {
+			for resp := range p.cli.Watch(p.ctx, p.key) {
+				if resp.Err() != nil || resp.Canceled {
+					return
+				}
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			}
+		}()
+	})
+}
+
+// Close stops watching.
+func (p *KeyProvider) Close() {
+	p.cancel()
+}
@@ -0,0 +1,149 @@
+/**
+ * Unit test cases for etcdconf
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 02:03:11
+ */
+
+package etcdconf
+
+import (
+	"context"
+	"github.com/chosen0ne/goconf"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/v3"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeClient is an in-memory etcd.
+type fakeClient struct {
+	mu      sync.Mutex
+	kvs     map[string]string
+	watches chan clientv3.WatchResponse
+}
+
+func newFakeClient(kvs map[string]string) *fakeClient {
+	return &fakeClient{kvs: kvs, watches: make(chan clientv3.WatchResponse)}
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	resp := &clientv3.GetResponse{}
+	for k, v := range c.kvs {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(v)})
+		}
+	}
+	sort.Slice(resp.Kvs, func(i, j int) bool { return string(resp.Kvs[i].Key) < string(resp.Kvs[j].Key) })
+	return resp, nil
+}
+
+func (c *fakeClient) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	return c.watches
+}
+
+func (c *fakeClient) put(key, val string) {
+	c.mu.Lock()
+	c.kvs[key] = val
+	c.mu.Unlock()
+	c.watches <- clientv3.WatchResponse{}
+}
+
+func TestLoad(t *testing.T) {
+	cli := newFakeClient(map[string]string{
+		"/app/name": "app", "/app/db/host": "localhost", "/app/db/pool/size": "10", "/other/x": "1",
+		"/application/x": "1",
+	})
+
+	conf, err := Load(context.Background(), cli, "/app/")
+	if err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if v, _ := conf.GetString("name"); v != "app" {
+		t.Errorf("name, val: %s", v)
+	}
+	if v, _ := conf.GetStringFrom("db", "host"); v != "localhost" {
+		t.Errorf("db.host, val: %s", v)
+	}
+	if v, _ := conf.GetIntFrom("db", "pool/size"); v != 10 {
+		t.Errorf("db.pool/size, val: %d", v)
+	}
+	if conf.HasItem("x") || conf.HasSection("other") {
+		t.Errorf("keys out of the prefix shouldn't be loaded")
+	}
+
+	// '/app' is the prefix '/app/', and the conf is resolved as a file is
+	t.Setenv("GOCONF_NAME", "env")
+	conf, err = Load(context.Background(), cli, "/app", goconf.WithEnvOverrides(""))
+	if err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if conf.HasSection("lication") || conf.HasSection("ication") {
+		t.Errorf("keys of another prefix shouldn't be loaded, sections: %v", conf.Sections(false))
+	}
+	if v, _ := conf.GetString("name"); v != "env" {
+		t.Errorf("name overridden by env, val: %s", v)
+	}
+
+	cli.kvs["/app/db"] = "conflict"
+	if _, err := Load(context.Background(), cli, "/app/"); goconf.ErrorCode(err) != goconf.E_FORMAT_VALUE {
+		t.Errorf("need an error for an item conflicting with a section, err: %v", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	cli := newFakeClient(map[string]string{"/app/port": "8080"})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	confs := make(chan *goconf.Conf)
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, cli, "/app/", func(conf *goconf.Conf, err error) {
+			if err != nil {
+				t.Errorf("failed to reload, err: %s", err)
+			}
+			confs <- conf
+		})
+	}()
+
+	cli.put("/app/port", "9090")
+	if v, _ := (<-confs).GetInt("port"); v != 9090 {
+		t.Errorf("port after change, val: %d", v)
+	}
+
+	cancel()
+	close(cli.watches)
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch should return the cause, err: %v", err)
+	}
+}
+
+func TestKeyProvider(t *testing.T) {
+	cli := newFakeClient(map[string]string{"/app/config.json": `{"port": 8080}`})
+	p := NewKeyProvider(cli, "/app/config.json")
+	defer p.Close()
+
+	conf, err := goconf.ParseProvider(p)
+	if err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetInt("port"); v != 8080 {
+		t.Errorf("port, val: %d", v)
+	}
+
+	changed := make(chan struct{}, 1)
+	p.Watch(changed)
+	cli.put("/app/config.json", `{"port": 9090}`)
+	<-changed
+	if conf, err = goconf.ParseProvider(p); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetInt("port"); v != 9090 {
+		t.Errorf("port after change, val: %d", v)
+	}
+}
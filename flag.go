@@ -0,0 +1,64 @@
+/**
+ * Command-line flags override config items. BindFlags defines a flag for
+ * every item, named like the item, and setting the flag sets the item. As
+ * flags are parsed after the config file and the environment, the
+ * precedence is flag > env > file.
+ *
+ *      e.g.
+ *          conf := New("app.conf", WithEnvOverrides("APP"))
+ *          if err := conf.Parse(); err != nil {
+ *              // handle err
+ *          }
+ *          conf.BindFlags(flag.CommandLine)
+ *          flag.Parse()    // e.g. '-server.port=9090 -name=app2'
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 23:52:13
+ */
+
+package goconf
+
+import (
+	"flag"
+	"fmt"
+	"github.com/chosen0ne/goutils"
+	"strings"
+)
+
+// BindFlags defines a flag in fs for every item, named by the key for global
+// items and by 'section.key' for the others. The default of a flag is the
+// value of its item. Items whose names are defined in fs already are
+// skipped, so the flags of the application win.
+func (conf *Conf) BindFlags(fs *flag.FlagSet) {
+	conf.Walk(func(section string, item *Item) error {
+		name := itemPath(section, item.key)
+		if fs.Lookup(name) == nil {
+			fs.Var(&itemFlag{item, name}, name, fmt.Sprintf("config item '%s'", name))
+		}
+		return nil
+	})
+}
+
+// An itemFlag is a flag.Value setting an item.
+type itemFlag struct {
+	item *Item
+	name string
+}
+
+func (f *itemFlag) String() string {
+	if f.item == nil {
+		return ""
+	}
+	return f.item.val
+}
+
+func (f *itemFlag) Set(val string) error {
+	val = strings.Trim(val, _SPACE_CHARS)
+	if val == "" {
+		return goutils.NewErr("an empty value of '%s'", f.item.key)
+	}
+
+	f.item.val, f.item.raw = val, ""
+	f.item.origin = origin{flag: f.name}
+	return nil
+}
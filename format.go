@@ -0,0 +1,290 @@
+/**
+ * Config files in other formats are parsed into the same sections and items
+ * by format parsers, which are registered by file extension. JSON is built
+ * in, and other formats are in subpackages which register themselves when
+ * imported:
+ *
+ *      e.g.
+ *          import _ "github.com/chosen0ne/goconf/yamlconf"
+ *
+ *          err := goconf.Load(confObj, "config.yaml")
+ *
+ *  Files with extensions not registered are parsed as goconf files. The
+ *  format can also be given by WithFormat, e.g. for a file without extension:
+ *
+ *      e.g.
+ *          conf := goconf.New("/etc/app/config", goconf.WithFormat("yaml"))
+ *
+ *  Most formats are trees of maps, which are mapped by MergeMap: top-level
+ *  maps are sections, and the other top-level values are global items.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 19:40:12
+ */
+
+package goconf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A FormatParser parses a config file from r into conf. 'path' is the path
+// of the file, used in errors.
+type FormatParser func(conf *Conf, r io.Reader, path string) error
+
+var (
+	formatsMu sync.RWMutex
+	formats   = make(map[string]FormatParser)
+)
+
+// RegisterFormat registers the parser of files with extension ext, e.g.
+// ".yaml". A later registration of the same extension replaces the former.
+func RegisterFormat(ext string, parser FormatParser) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[strings.ToLower(ext)] = parser
+}
+
+// _NATIVE_EXT is the extension of goconf files, used by WithFormat.
+const _NATIVE_EXT = ".conf"
+
+func formatParser(path string) FormatParser {
+	parser, _ := lookupFormat(filepath.Ext(path))
+	return parser
+}
+
+func lookupFormat(ext string) (FormatParser, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	parser, ok := formats[strings.ToLower(ext)]
+	return parser, ok
+}
+
+// formatParser returns the parser of file 'path', which is nil for goconf
+// files. The format given by WithFormat overrides the extension of the
+// config file, but not of the files it includes.
+func (conf *Conf) formatParser(path string) (FormatParser, error) {
+	format := conf.opts.format
+	if format == "" || path != conf.filePath {
+		return formatParser(path), nil
+	}
+
+	ext := "." + strings.TrimPrefix(strings.ToLower(format), ".")
+	if ext == _NATIVE_EXT {
+		return nil, nil
+	}
+	parser, ok := lookupFormat(ext)
+	if !ok {
+		return nil, &ParseError{Code: E_FORMAT, File: path, Msg: fmt.Sprintf("unknown format '%s'", format)}
+	}
+
+	return parser, nil
+}
+
+func (conf *Conf) parseWith(parser FormatParser, r io.Reader, path string) error {
+	err := parser(conf, r, path)
+
+	var perr *ParseError
+	if errors.As(err, &perr) && perr.File == "" {
+		perr.File = path
+	}
+
+	return err
+}
+
+// ParseMap parses a tree of values, as MergeMap merges it, into a new Conf.
+// It's finished as a parsed file is, by the profile, env overrides,
+// interpolation, decryption and secret resolvers. The options are the ones
+// of New.
+func ParseMap(m map[string]interface{}, opts ...Option) (*Conf, error) {
+	return ParseMapContext(context.Background(), m, opts...)
+}
+
+// ParseMapContext is ParseMap, whose secret resolvers stop once ctx is
+// done.
+func ParseMapContext(ctx context.Context, m map[string]interface{}, opts ...Option) (*Conf, error) {
+	conf := New("", opts...)
+	conf.ctx = ctx
+	defer func() { conf.ctx = nil }()
+	if err := conf.MergeMap(m); err != nil {
+		return nil, err
+	}
+	if err := conf.resolve(); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// MergeMap merges a tree of values into the conf. Members of m with map
+// values are sections, and the other members are global items. Values of
+// items are strings, numbers, booleans, times, or slices of them, which are
+// array items whose elements are joined by the element separator. Keys of
+// items may be '[@name@sep]' as in config files, e.g. '[@hosts@,]', to set
+// the separator of the item. nil values are skipped. Existing sections are merged, and existing items are
+// overridden. New sections and items are added by name, see MergeMapInOrder
+// to keep the order of a document.
+func (conf *Conf) MergeMap(m map[string]interface{}) error {
+	return conf.MergeMapInOrder(m, nil)
+}
+
+// MergeMapInOrder is MergeMap, adding new sections and items in the order of
+// keys, e.g. the order of the document m is decoded from. Keys are paths of
+// members of m, 'key' or 'section.key'. Members not in keys are added after,
+// by name.
+func (conf *Conf) MergeMapInOrder(m map[string]interface{}, keys []string) error {
+	if err := conf.loadSections(); err != nil {
+		return err
+	}
+	for _, key := range orderKeys(m, keys, "") {
+		val := m[key]
+		members, ok := val.(map[string]interface{})
+		if !ok {
+			if err := conf.setMapItem(conf.sections[_GLOBAL], _GLOBAL, key, val); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sec, ok := conf.sections[key]
+		if !ok {
+			sec = newSection()
+			conf.sections[key] = sec
+			if err := conf.checkSections("", 0); err != nil {
+				return err
+			}
+		}
+		for _, k := range orderKeys(members, keys, key+string(_PATH_SEP)) {
+			if err := conf.setMapItem(sec, key, k, members[k]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// orderKeys returns the keys of m in the order of paths, which are 'prefix'
+// followed by a key, and then the others by name. With no prefix, a path
+// 'section.key' orders 'section'.
+func orderKeys(m map[string]interface{}, paths []string, prefix string) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool, len(m))
+	for _, path := range paths {
+		key, ok := strings.CutPrefix(path, prefix)
+		if !ok {
+			continue
+		}
+		if dot := strings.IndexByte(key, _PATH_SEP); prefix == "" && dot >= 0 {
+			key = key[:dot]
+		}
+		if _, ok := m[key]; ok && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, key := range sortedKeys(m) {
+		if !seen[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// setMapItem sets item 'key' of sec, section 'secName', by a value of a
+// tree. The key may be '[@name@sep]' of an array item.
+func (conf *Conf) setMapItem(sec *section, secName, key string, val interface{}) error {
+	if val == nil {
+		return nil
+	}
+
+	key, sep, err := parseArrayKey(key, conf.opts.elementSep)
+	if err != nil {
+		return &ParseError{Code: E_FORMAT_VALUE, Msg: err.Error()}
+	}
+	name := itemPath(secName, key)
+	if rows, ok := val.(matrixRows); ok {
+		return conf.setMapValue(sec, key, name, strings.Join(rows, string(_MATRIX_ROW_SEP)), _MATRIX_ROW_SEP)
+	}
+	if arr, ok := val.([]interface{}); ok {
+		if sep == 0 {
+			sep = conf.opts.elementSep
+		}
+		eles := make([]string, len(arr))
+		for idx, ele := range arr {
+			s, err := scalarString(ele)
+			if err != nil {
+				return &ParseError{Code: E_FORMAT_VALUE, Msg: fmt.Sprintf("'%s': %s", name, err)}
+			}
+			if strings.IndexByte(s, sep) >= 0 {
+				return &ParseError{Code: E_FORMAT_VALUE,
+					Msg: fmt.Sprintf("'%s': element '%s' contains the element separator", name, s)}
+			}
+			eles[idx] = s
+		}
+		if len(eles) == 0 {
+			return nil
+		}
+		return conf.setMapValue(sec, key, name, strings.Join(eles, string(sep)), sep)
+	}
+
+	s, err := scalarString(val)
+	if err != nil {
+		return &ParseError{Code: E_FORMAT_VALUE, Msg: fmt.Sprintf("'%s': %s", name, err)}
+	}
+	if s == "" {
+		return &ParseError{Code: E_PARSE_EMPTY_VALUE, Msg: fmt.Sprintf("an empty value of '%s'", name)}
+	}
+	return conf.setMapValue(sec, key, name, s, sep)
+}
+
+// setMapValue sets item 'key' of sec. An array value is joined by sep, which
+// is 0 for scalars.
+func (conf *Conf) setMapValue(sec *section, key, name, val string, sep byte) error {
+	if sec.get(key) == nil && conf.opts.limits.MaxItems > 0 {
+		if err := conf.countItem("", 0); err != nil {
+			return err
+		}
+	}
+	item, err := conf.setItem(sec, name, key, val)
+	if err != nil {
+		return &ParseError{Code: E_MERGE, Msg: err.Error()}
+	}
+	if sep != 0 {
+		item.sep = sep
+	}
+	return nil
+}
+
+func scalarString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return strings.Trim(v, _SPACE_CHARS), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case uint64:
+		return strconv.FormatUint(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case fmt.Stringer:
+		// e.g. json.Number
+		return v.String(), nil
+	}
+
+	return "", fmt.Errorf("not supported value %v", val)
+}
@@ -0,0 +1,168 @@
+/**
+ * A read-only fs.FS view of a parsed config, so file-oriented consumers
+ * (template engines, file servers) can read config values.
+ *
+ *      e.g. config file:
+ *          > name: app
+ *          > [db]
+ *          > host: localhost
+ *
+ *      is exposed as:
+ *          name        => "app"
+ *          db/         => directory
+ *          db/host     => "localhost"
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 18:30:02
+ */
+
+package goconf
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FS returns a snapshot of the conf as a read-only file system. Global items
+// are files in the root directory, and each section is a directory of its
+// item files. A file holds the value of its item without a trailing newline.
+// If a global item has the same name as a section, only the section is shown.
+// Keys which aren't valid file names, e.g. containing '/', are left out.
+func (conf *Conf) FS() fs.FS {
+	conf.loadSections()
+	root := &fsNode{name: ".", dir: true}
+	for _, name := range conf.Sections(false) {
+		if !validFileName(name) {
+			continue
+		}
+		dir := &fsNode{name: name, dir: true}
+		dir.addItems(conf.sections[name], nil)
+		root.children = append(root.children, dir)
+	}
+	root.addItems(conf.sections[_GLOBAL], root.children)
+
+	return &confFS{root, time.Now()}
+}
+
+type confFS struct {
+	root    *fsNode
+	modTime time.Time
+}
+
+// A fsNode is a file or a directory.
+type fsNode struct {
+	name     string
+	dir      bool
+	data     []byte
+	children []*fsNode // sorted by name
+}
+
+func (n *fsNode) addItems(s *section, hidden []*fsNode) {
+	names := make(map[string]bool, len(hidden))
+	for _, h := range hidden {
+		names[h.name] = true
+	}
+
+	for _, key := range s.keys {
+		if names[key] || !validFileName(key) {
+			continue
+		}
+		n.children = append(n.children, &fsNode{name: key, data: []byte(s.m[key].val)})
+	}
+	sort.Slice(n.children, func(i, j int) bool { return n.children[i].name < n.children[j].name })
+}
+
+func (n *fsNode) child(name string) *fsNode {
+	idx := sort.Search(len(n.children), func(i int) bool { return n.children[i].name >= name })
+	if idx < len(n.children) && n.children[idx].name == name {
+		return n.children[idx]
+	}
+	return nil
+}
+
+func validFileName(name string) bool {
+	return fs.ValidPath(name) && name != "." && !strings.Contains(name, "/")
+}
+
+func (cfs *confFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	node := cfs.root
+	if name != "." {
+		for _, part := range strings.Split(name, "/") {
+			if node = node.child(part); node == nil {
+				return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+			}
+		}
+	}
+
+	info := &fsInfo{node, cfs.modTime}
+	if node.dir {
+		return &fsDir{info: info}, nil
+	}
+	return &fsFile{info, bytes.NewReader(node.data)}, nil
+}
+
+type fsInfo struct {
+	node    *fsNode
+	modTime time.Time
+}
+
+func (fi *fsInfo) Name() string               { return fi.node.name }
+func (fi *fsInfo) Size() int64                { return int64(len(fi.node.data)) }
+func (fi *fsInfo) ModTime() time.Time         { return fi.modTime }
+func (fi *fsInfo) IsDir() bool                { return fi.node.dir }
+func (fi *fsInfo) Sys() interface{}           { return nil }
+func (fi *fsInfo) Info() (fs.FileInfo, error) { return fi, nil }
+func (fi *fsInfo) Type() fs.FileMode          { return fi.Mode().Type() }
+
+func (fi *fsInfo) Mode() fs.FileMode {
+	if fi.node.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+type fsFile struct {
+	info *fsInfo
+	*bytes.Reader
+}
+
+func (f *fsFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *fsFile) Close() error               { return nil }
+
+type fsDir struct {
+	info   *fsInfo
+	offset int
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *fsDir) Close() error               { return nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.node.name, Err: fs.ErrInvalid}
+}
+
+func (d *fsDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	children := d.info.node.children[d.offset:]
+	if count > 0 && len(children) == 0 {
+		return nil, io.EOF
+	}
+	if count > 0 && count < len(children) {
+		children = children[:count]
+	}
+
+	entries := make([]fs.DirEntry, len(children))
+	for idx, c := range children {
+		entries[idx] = &fsInfo{c, d.info.modTime}
+	}
+	d.offset += len(children)
+
+	return entries, nil
+}
@@ -0,0 +1,202 @@
+//go:build ignore
+
+/**
+ * Generator of conf_panic.go, run by 'go generate'.
+ *
+ * For every getter of Conf in the form of
+ *          func (conf *Conf) GetXxx(args) (T, error)
+ *  a panic-style counterpart is generated:
+ *          func (conf *Conf) ToXxx(args) T
+ *  which panics with the error instead of returning it. So whenever a new
+ *  getter is added, rerun 'go generate' and the two API surfaces stay the
+ *  same. TestPanicGettersInSync fails if they drift.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 14:48:26
+ */
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const _OUTPUT = "conf_panic.go"
+
+const _HEADER = `// Code generated by 'go run gen_panic.go'; DO NOT EDIT.
+
+package goconf
+`
+
+type getter struct {
+	name    string
+	params  string // parameter list of the declaration
+	args    string // arguments passed to the getter
+	results string // type of the value returned
+	imports []string
+}
+
+func main() {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	var getters []getter
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || file == _OUTPUT || file == "gen_panic.go" {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && isGetter(fn) {
+				getters = append(getters, newGetter(fset, f, fn))
+			}
+		}
+	}
+	sort.Slice(getters, func(i, j int) bool { return getters[i].name < getters[j].name })
+
+	buf := bytes.NewBufferString(_HEADER)
+	writeImports(buf, getters)
+	for _, g := range getters {
+		fmt.Fprintf(buf, `
+// To%[1]s is like Get%[1]s, but panics on error.
+func (conf *Conf) To%[1]s(%[2]s) %[4]s {
+	val, err := conf.Get%[1]s(%[3]s)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+`, g.name, g.params, g.args, g.results)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(_OUTPUT, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// isGetter reports whether fn is 'func (conf *Conf) GetXxx(...) (T, error)'.
+func isGetter(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 || !strings.HasPrefix(fn.Name.Name, "Get") {
+		return false
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	if ident, ok := star.X.(*ast.Ident); !ok || ident.Name != "Conf" {
+		return false
+	}
+
+	results := fn.Type.Results
+	if results == nil || results.NumFields() != 2 {
+		return false
+	}
+	last := results.List[len(results.List)-1].Type
+	ident, ok := last.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// writeImports imports the packages of types in the signatures of getters.
+func writeImports(buf *bytes.Buffer, getters []getter) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, g := range getters {
+		for _, path := range g.imports {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return
+	}
+
+	sort.Strings(paths)
+	buf.WriteString("\nimport (\n")
+	for _, path := range paths {
+		fmt.Fprintf(buf, "\t%q\n", path)
+	}
+	buf.WriteString(")\n")
+}
+
+// usedImports returns the paths of packages of f which node refers to.
+func usedImports(f *ast.File, node ast.Node) []string {
+	var paths []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		for _, imp := range f.Imports {
+			path := strings.Trim(imp.Path.Value, `"`)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == pkg.Name {
+				paths = append(paths, path)
+			}
+		}
+		return true
+	})
+
+	return paths
+}
+
+func newGetter(fset *token.FileSet, f *ast.File, fn *ast.FuncDecl) getter {
+	var params, args []string
+	for _, field := range fn.Type.Params.List {
+		typ := render(fset, field.Type)
+		for _, name := range field.Names {
+			params = append(params, name.Name+" "+typ)
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				args = append(args, name.Name+"...")
+			} else {
+				args = append(args, name.Name)
+			}
+		}
+	}
+
+	return getter{
+		name:    strings.TrimPrefix(fn.Name.Name, "Get"),
+		params:  strings.Join(params, ", "),
+		args:    strings.Join(args, ", "),
+		results: render(fset, fn.Type.Results.List[0].Type),
+		imports: usedImports(f, fn.Type),
+	}
+}
+
+func render(fset *token.FileSet, node ast.Node) string {
+	buf := bytes.Buffer{}
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		log.Fatal(err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,51 @@
+/**
+ * Generic getters and loaders. Get[T] converts an item to any type a field
+ * can be loaded into, so call sites don't pick a GetXxx for every type.
+ * LoadAs[T] returns a loaded config object without boilerplate.
+ *
+ *      e.g.
+ *          port, err := goconf.Get[int](conf, "port")
+ *          timeout, err := goconf.Get[time.Duration](conf, "timeout")
+ *          hosts, err := goconf.Get[[]string](conf, "db.hosts")
+ *          cfg, err := goconf.LoadAs[Config]("app.conf")
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:05:31
+ */
+
+package goconf
+
+import (
+	"reflect"
+)
+
+// Get returns item 'key' converted to T, as a field of type T is loaded.
+// T may be a number, string or bool type, a slice or map of them, or a
+// type with a converter or an Unmarshaler, e.g. a time.Duration.
+func Get[T any](conf *Conf, key string) (T, error) {
+	var val T
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return val, err
+	}
+
+	v := reflect.ValueOf(&val).Elem()
+	l := &loader{conf: conf}
+	if err := l.setValue(key, item, &v); err != nil {
+		var zero T
+		return zero, err
+	}
+	return val, nil
+}
+
+// LoadAs allocates a config object of type T, and loads it from the file
+// as Load does. It's named LoadAs, as Load already takes a pointer.
+//
+//	cfg, err := goconf.LoadAs[Config]("app.conf")
+func LoadAs[T any](configFile string, opts ...Option) (*T, error) {
+	obj := new(T)
+	if err := Load(obj, configFile, opts...); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,70 @@
+/**
+ * Multi-line values keep SQL snippets, templates and PEM blocks verbatim in
+ * a config file. A value is '"""' or '<<MARKER', and the following lines up
+ * to a line of '"""' or 'MARKER' are the value, joined by '\n'.
+ *
+ *      e.g.
+ *          > query: """
+ *          >   SELECT id FROM users
+ *          >   WHERE age > 18
+ *          > """
+ *          > cert: <<EOF
+ *          > -----BEGIN CERTIFICATE-----
+ *          > ...
+ *          > EOF
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 06:31:47
+ */
+
+package goconf
+
+import (
+	"io"
+	"strings"
+)
+
+const (
+	_TRIPLE_QUOTE   = `"""`
+	_HEREDOC_PREFIX = "<<"
+)
+
+// heredocMarker returns the end marker of a multi-line value val. ok is
+// false if val isn't multi-line.
+func heredocMarker(val string) (marker string, ok bool) {
+	if val == _TRIPLE_QUOTE {
+		return val, true
+	}
+
+	marker = strings.TrimPrefix(val, _HEREDOC_PREFIX)
+	if len(marker) == len(val) || marker == "" {
+		return "", false
+	}
+	for idx, c := range marker {
+		if !(c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || idx > 0 && c >= '0' && c <= '9') {
+			return "", false
+		}
+	}
+	return marker, true
+}
+
+// readHeredoc reads the lines of a multi-line value up to the line of
+// marker. ok is false if the input ends before it.
+func readHeredoc(buf lineReader, marker string, lineNo *int) (val string, ok bool, err error) {
+	var lines []string
+	for {
+		line, err := buf.ReadString(_NEWLINE)
+		if len(line) == 0 && err == io.EOF {
+			return "", false, nil
+		} else if err != nil && err != io.EOF {
+			return "", false, readErr(err)
+		}
+		*lineNo++
+
+		line = strings.TrimRight(line, "\r\n")
+		if strings.Trim(line, _SPACE_CHARS) == marker {
+			return strings.Join(lines, "\n"), true, nil
+		}
+		lines = append(lines, line)
+	}
+}
@@ -0,0 +1,172 @@
+/**
+ * IDSet is a compact set of numeric IDs, written as a list of IDs and
+ * intervals, so huge sets aren't materialized as slices.
+ *
+ *      e.g. config file:
+ *          > ids: 1-1000000 step 2, 2000000, 3000000-3000010
+ *
+ *      is the odd IDs up to 999999, 2000000, and 3000000 to 3000010. An
+ *      interval includes both ends, and the step is 1 by default.
+ *
+ *          ids, err := conf.GetIDSet("ids")
+ *          it := ids.Iterator()
+ *          for id, ok := it.Next(); ok; id, ok = it.Next() {
+ *              // use id
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 01:02:48
+ */
+
+package goconf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	_ID_SEP      = ','
+	_ID_INTERVAL = '-'
+	_ID_STEP     = "step"
+)
+
+type idInterval struct {
+	first int64
+	last  int64
+	step  int64
+}
+
+// count returns the number of IDs in the interval.
+func (in *idInterval) count() int64 {
+	return (in.last-in.first)/in.step + 1
+}
+
+func (in *idInterval) contains(id int64) bool {
+	return id >= in.first && id <= in.last && (id-in.first)%in.step == 0
+}
+
+// An IDSet is a list of intervals of IDs. IDs are in the order of the
+// config, and aren't deduplicated.
+type IDSet struct {
+	intervals []idInterval
+}
+
+// Len returns the number of IDs.
+func (s *IDSet) Len() int64 {
+	var n int64
+	for idx := range s.intervals {
+		n += s.intervals[idx].count()
+	}
+	return n
+}
+
+func (s *IDSet) Contains(id int64) bool {
+	for idx := range s.intervals {
+		if s.intervals[idx].contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Iterator returns an iterator of the IDs, in the order of the config.
+func (s *IDSet) Iterator() *IDIterator {
+	return &IDIterator{set: s}
+}
+
+type IDIterator struct {
+	set  *IDSet
+	idx  int   // interval being iterated
+	next int64 // offset of the next ID in the interval
+}
+
+// Next returns the next ID, and false if there are no more.
+func (it *IDIterator) Next() (int64, bool) {
+	for it.idx < len(it.set.intervals) {
+		in := &it.set.intervals[it.idx]
+		if it.next < in.count() {
+			id := in.first + it.next*in.step
+			it.next++
+			return id, true
+		}
+		it.idx++
+		it.next = 0
+	}
+	return 0, false
+}
+
+// ToIDSet parses the value as IDs and intervals like '1-100 step 2',
+// separated by ','.
+func (item *Item) ToIDSet() (*IDSet, error) {
+	set := &IDSet{}
+	for _, part := range strings.Split(item.val, string(_ID_SEP)) {
+		in, err := parseIDInterval(strings.Trim(part, _SPACE_CHARS))
+		if err != nil {
+			return nil, item.typeErr("id set", err)
+		}
+		set.intervals = append(set.intervals, in)
+	}
+
+	return set, nil
+}
+
+func parseIDInterval(s string) (idInterval, error) {
+	in := idInterval{step: 1}
+	fields := strings.Fields(s)
+	switch {
+	case len(fields) == 3 && fields[1] == _ID_STEP:
+		step, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return in, err
+		}
+		if step <= 0 {
+			return in, fmt.Errorf("step of '%s' must be positive", s)
+		}
+		in.step = step
+	case len(fields) != 1:
+		return in, fmt.Errorf("bad interval '%s'", s)
+	}
+
+	// Skip the sign of the first ID
+	bounds := fields[0]
+	dash := -1
+	if len(bounds) > 1 {
+		if idx := strings.IndexByte(bounds[1:], _ID_INTERVAL); idx >= 0 {
+			dash = idx + 1
+		}
+	}
+
+	var err error
+	if dash < 0 {
+		if in.step != 1 {
+			return in, fmt.Errorf("step of a single ID '%s'", s)
+		}
+		in.first, err = strconv.ParseInt(bounds, 10, 64)
+		in.last = in.first
+		return in, err
+	}
+
+	if in.first, err = strconv.ParseInt(bounds[:dash], 10, 64); err != nil {
+		return in, err
+	}
+	if in.last, err = strconv.ParseInt(bounds[dash+1:], 10, 64); err != nil {
+		return in, err
+	}
+	if in.first > in.last {
+		return in, fmt.Errorf("bad interval '%s', %d > %d", s, in.first, in.last)
+	}
+
+	return in, nil
+}
+
+// GetIDSet returns item 'key' as an IDSet, see Item.ToIDSet.
+func (conf *Conf) GetIDSet(key string) (*IDSet, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToIDSet()
+}
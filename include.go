@@ -0,0 +1,119 @@
+/**
+ * Directives are lines starting with '!'. They are handled while parsing
+ * and don't produce config items by themselves.
+ *
+ *      > !include-host conf.d/%H.conf
+ *
+ *  'include-host' parses another file in place, if the file exists. It's
+ *  used for per-host or per-environment overrides. In the path:
+ *          %H is replaced by the hostname
+ *          %E is replaced by the value of env variable GOCONF_ENV
+ *          %% is replaced by '%'
+ *  A relative path is relative to the directory of the including file.
+ *  Items of the included file override the items read so far, and its
+ *  sections are merged into the existing sections with the same name.
+ *  After the included file, parsing continues in the section where the
+ *  directive is. As overrides must come after the items they override,
+ *  the directive is normally put at the end of the file.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 11:32:05
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"github.com/chosen0ne/goutils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	_ENV_VAR           = "GOCONF_ENV"
+	_MAX_INCLUDE_DEPTH = 8
+)
+
+// hostname is replaceable in tests.
+var hostname = os.Hostname
+
+func (conf *Conf) directive(line, path string, lineNo int) error {
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return parseErr(path, lineNo, E_DIRECTIVE, "an empty directive")
+	}
+
+	switch parts[0] {
+	case "include-host":
+		if len(parts) != 2 {
+			return parseErr(path, lineNo, E_DIRECTIVE, "include-host needs a path, line: !%s", line)
+		}
+		return conf.includeHost(parts[1], path, lineNo)
+	default:
+		return parseErr(path, lineNo, E_DIRECTIVE, "unknown directive '%s'", parts[0])
+	}
+}
+
+func (conf *Conf) includeHost(pattern, path string, lineNo int) error {
+	includePath, err := expandIncludePath(pattern)
+	if err != nil {
+		return parseErr(path, lineNo, E_DIRECTIVE, "%s", err)
+	}
+	if !filepath.IsAbs(includePath) && path != "" {
+		includePath = filepath.Join(filepath.Dir(path), includePath)
+	}
+
+	f, err := os.Open(includePath)
+	if os.IsNotExist(err) {
+		// optional, nothing to override
+		return nil
+	} else if err != nil {
+		return &ParseError{Code: E_INCLUDE, File: path, Line: lineNo, Msg: err.Error(), Err: err}
+	}
+	defer f.Close()
+
+	if conf.includeDepth >= _MAX_INCLUDE_DEPTH {
+		return parseErr(path, lineNo, E_INCLUDE, "too deep to include '%s'", includePath)
+	}
+
+	cur := conf.cur
+	conf.cur = conf.sections[_GLOBAL]
+	conf.includeDepth++
+	err = conf.parseFrom(bufio.NewReader(f), includePath, true)
+	conf.includeDepth--
+	conf.cur = cur
+
+	return err
+}
+
+func expandIncludePath(pattern string) (string, error) {
+	var expanded []byte
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' {
+			expanded = append(expanded, pattern[i])
+			continue
+		}
+
+		i++
+		if i == len(pattern) {
+			return "", goutils.NewErr("incomplete '%%' in include path: %s", pattern)
+		}
+		switch pattern[i] {
+		case 'H':
+			host, err := hostname()
+			if err != nil {
+				return "", goutils.WrapErr(err)
+			}
+			expanded = append(expanded, host...)
+		case 'E':
+			expanded = append(expanded, os.Getenv(_ENV_VAR)...)
+		case '%':
+			expanded = append(expanded, '%')
+		default:
+			return "", goutils.NewErr("unknown '%%%c' in include path: %s", pattern[i], pattern)
+		}
+	}
+
+	return string(expanded), nil
+}
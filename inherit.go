@@ -0,0 +1,60 @@
+/**
+ * Section inheritance. A section declaring a parent by '[child : parent]'
+ * starts with the items of the parent, which its own items override, e.g.
+ *
+ *      > [db]
+ *      > host: db1
+ *      > port: 3306
+ *      > [db_replica : db]
+ *      > host: db2
+ *
+ *      'db_replica.port' is 3306, and 'db_replica.host' is 'db2'.
+ *
+ *  The parent must be declared before the child. The ':' must follow a
+ *  space, so '[prefix:name]' of section maps and profiles is a name.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 17:32:19
+ */
+
+package goconf
+
+import (
+	"strings"
+)
+
+// splitParent splits the name of a section header into the name of the
+// section, and the name of its parent if it's declared.
+func splitParent(header string) (name, parent string) {
+	for i := 1; i < len(header); i++ {
+		if header[i] == _SECTION_PREFIX_SEP && (header[i-1] == ' ' || header[i-1] == '\t') {
+			return strings.Trim(header[:i], _SPACE_CHARS), strings.Trim(header[i+1:], _SPACE_CHARS)
+		}
+	}
+	return header, ""
+}
+
+// inherit copies the items of section 'parent' into sec, except the ones
+// sec already has, e.g. when a section is reopened by an overlay. The
+// copies keep the origins of the items of the parent.
+func (conf *Conf) inherit(sec *section, name, parent, path string, lineNo int) error {
+	p, ok := conf.sections[parent]
+	if !ok || parent == "" {
+		return parseErr(path, lineNo, E_PARSE_PARENT, "parent '%s' of section '%s' isn't declared before", parent, name)
+	}
+
+	same := func(s string) string { return s }
+	for _, key := range p.keys {
+		if sec.get(key) != nil {
+			continue
+		}
+		if conf.opts.limits.MaxItems > 0 {
+			if err := conf.countItem(path, lineNo); err != nil {
+				return err
+			}
+		}
+		sec.set(key, p.m[key].clone(same))
+	}
+
+	return nil
+}
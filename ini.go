@@ -0,0 +1,52 @@
+/**
+ * INI config files. They are parsed as goconf files, except that both '='
+ * and ':' separate keys from values, lines starting with ';' are comments
+ * as well, and a repeated section continues the former one.
+ *
+ *      e.g.
+ *          > ; app config
+ *          > name = app
+ *          > [db]
+ *          > host = localhost
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 22:31:08
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+const _INI_COMMENT_TAG = ';'
+
+func init() {
+	RegisterFormat(".ini", parseINI)
+}
+
+func parseINI(conf *Conf, r io.Reader, path string) error {
+	kvSep := conf.opts.kvSep
+	conf.opts.kvSep = AutoKVSeparator
+	defer func() {
+		conf.opts.kvSep = kvSep
+	}()
+
+	return conf.parseFrom(&iniReader{bufio.NewReader(r)}, path, true)
+}
+
+// An iniReader reads ';' comment lines as empty lines, so the line numbers
+// of errors are kept.
+type iniReader struct {
+	*bufio.Reader
+}
+
+func (r *iniReader) ReadString(delim byte) (string, error) {
+	line, err := r.Reader.ReadString(delim)
+	if trimmed := strings.TrimLeft(line, _SPACE_CHARS); trimmed != "" && trimmed[0] == _INI_COMMENT_TAG {
+		return string(delim), err
+	}
+	return line, err
+}
@@ -0,0 +1,147 @@
+/**
+ * Interpolation functions pull parts of values from the environment while
+ * parsing, by WithInterpolation. '${name:arg}' is replaced by the result of
+ * function 'name' of arg, and '${name}' by the result of it without arg.
+ *
+ *      e.g. config file:
+ *          > dsn: mysql://app:${file:/run/secrets/db_password}@${env:DB_HOST}/app
+ *          > node: ${hostname}
+ *
+ *  The builtin functions are 'env', the value of an env variable, 'file',
+ *  the content of a file without its final line end, e.g. a secret mounted
+ *  by the orchestrator, and 'hostname'. A relative path of 'file' is
+ *  relative to the config file. Other functions are registered by
+ *  RegisterInterpolation, and '$${' is a literal '${'. Values with parts of
+ *  'file' or of registered functions are secrets, see IsSecret.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 18:02:47
+ */
+
+package goconf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	_INTERP_LEFT  = "${"
+	_INTERP_RIGHT = '}'
+	_INTERP_SEP   = ':'
+	_INTERP_ESC   = "$${"
+)
+
+// An interpolation returns the value of '${name:arg}' in the value of item.
+type interpolation func(item *Item, arg string) (string, error)
+
+// interpolations are the functions of '${name:arg}' by name, guarded by
+// interpolationsLock as functions may be registered while parsing.
+var interpolationsLock sync.RWMutex
+var interpolations = map[string]interpolation{
+	"env": func(item *Item, name string) (string, error) {
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.New("env variable " + name + " isn't set")
+		}
+		return val, nil
+	},
+	"file": func(item *Item, path string) (string, error) {
+		content, err := readItemFile(&Item{val: path, origin: item.origin})
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	},
+	"hostname": func(item *Item, arg string) (string, error) {
+		return os.Hostname()
+	},
+}
+
+// secretInterpolations are the functions whose results are secrets, guarded
+// by interpolationsLock.
+var secretInterpolations = map[string]bool{"file": true}
+
+// RegisterInterpolation makes '${name:arg}' replaced by fn(arg), and
+// '${name}' by fn(""). It replaces the function of name, if any, including
+// the builtin ones. Results of fn are secrets, as it may read e.g. a vault.
+func RegisterInterpolation(name string, fn func(arg string) (string, error)) {
+	interpolationsLock.Lock()
+	defer interpolationsLock.Unlock()
+
+	interpolations[name] = func(item *Item, arg string) (string, error) {
+		return fn(arg)
+	}
+	secretInterpolations[name] = true
+}
+
+// WithInterpolation makes parsing replace '${name:arg}' in values by the
+// interpolation functions, see RegisterInterpolation.
+func WithInterpolation() Option {
+	return func(o *options) {
+		o.interpolation = true
+	}
+}
+
+// interpolate replaces '${name:arg}' in the values of the items.
+func (conf *Conf) interpolate() error {
+	return conf.Walk(func(section string, item *Item) error {
+		if !strings.Contains(item.val, _INTERP_LEFT) {
+			return nil
+		}
+
+		val, secret, err := interpolateValue(item)
+		if err != nil {
+			return &ParseError{Code: E_INTERPOLATE, File: item.origin.file, Line: item.origin.line,
+				Msg: fmt.Sprintf("failed to interpolate '%s': %s", itemPath(section, item.key), err), Err: err}
+		}
+		item.raw = item.Raw()
+		item.val = val
+		item.origin.secret = item.origin.secret || secret
+		return nil
+	})
+}
+
+// interpolateValue returns the value of the item with '${name:arg}'
+// replaced, and whether a part of it is a secret.
+func interpolateValue(item *Item) (string, bool, error) {
+	interpolationsLock.RLock()
+	defer interpolationsLock.RUnlock()
+
+	var sb strings.Builder
+	secret := false
+	s := item.val
+	for {
+		idx := strings.Index(s, _INTERP_LEFT)
+		if idx < 0 {
+			sb.WriteString(s)
+			return sb.String(), secret, nil
+		}
+		if strings.HasPrefix(s[max(idx-1, 0):], _INTERP_ESC) {
+			sb.WriteString(s[:idx-1] + _INTERP_LEFT)
+			s = s[idx+len(_INTERP_LEFT):]
+			continue
+		}
+		sb.WriteString(s[:idx])
+
+		end := strings.IndexByte(s[idx:], _INTERP_RIGHT)
+		if end < 0 {
+			return "", false, errors.New("no '}' of '${'")
+		}
+		name, arg, _ := strings.Cut(s[idx+len(_INTERP_LEFT):idx+end], string(_INTERP_SEP))
+		fn, ok := interpolations[name]
+		if !ok {
+			return "", false, errors.New("unknown function '" + name + "'")
+		}
+		val, err := fn(item, arg)
+		if err != nil {
+			return "", false, err
+		}
+		secret = secret || secretInterpolations[name]
+		sb.WriteString(val)
+		s = s[idx+end+1:]
+	}
+}
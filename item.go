@@ -7,27 +7,102 @@
 package goconf
 
 import (
-	"github.com/chosen0ne/goutils"
+	"encoding/base64"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	_BASE64_PREFIX = "@base64:" // marks a base64 value, e.g. 'cert: @base64:LS0t...'
+	_DIGIT_SEP     = '_'        // separates digits of numbers, e.g. '1_000_000'
+	_PERCENT       = "%"
 )
 
 // ------- Item ------- //
 type Item struct {
-	key string
-	val string
+	key         string
+	val         string
+	raw         string // the value as written if it isn't val, see Raw
+	annotations map[string]string
+	origin      origin
+	sep         byte                       // element separator, 0 for the default ' '
+	conv        atomic.Pointer[conversion] // the last conversion, see cached
+}
+
+// origin is where the value of an item comes from.
+type origin struct {
+	file   string
+	line   int
+	env    string // the variable overriding the value
+	flag   string // the flag overriding the value, see BindFlags
+	secret bool   // decrypted or resolved from a secret manager
 }
 
 func (item *Item) Key() string {
 	return item.key
 }
 
+// Raw returns the value as written in the file: the text after the
+// separator with its spaces, before any processing, e.g. unescaping or
+// decryption. A value of continued lines is the joined text, and a value
+// which isn't written as one piece, e.g. a multi-line value, a merged value
+// or a value set by an env variable, is the value itself.
+func (item *Item) Raw() string {
+	if item.raw == "" {
+		return item.val
+	}
+	return item.raw
+}
+
+// Source returns where the value comes from, e.g. 'app.conf:12',
+// 'env GOCONF_DB_PORT' or 'flag -db.port', and "" if unknown.
+func (item *Item) Source() string {
+	return item.origin.String()
+}
+
+func (o origin) String() string {
+	switch {
+	case o.flag != "":
+		return "flag -" + o.flag
+	case o.env != "":
+		return "env " + o.env
+	case o.line > 0:
+		return o.file + ":" + strconv.Itoa(o.line)
+	default:
+		return o.file
+	}
+}
+
 func (item *Item) String() string {
 	return item.key + "=>" + item.val
 }
 
 func (item *Item) ToInt() (int64, error) {
-	return strconv.ParseInt(item.val, 10, 64)
+	val, err := item.cached("int", "", func() (interface{}, error) {
+		val, err := parseInt(item.val)
+		if err != nil {
+			return nil, item.typeErr("int", err)
+		}
+		return val, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return val.(int64), nil
+}
+
+// ToUint parses the value as an unsigned int, so values up to the max of
+// uint64 are read, and negative values are errors.
+func (item *Item) ToUint() (uint64, error) {
+	val, err := parseUint(item.val)
+	if err != nil {
+		return 0, item.typeErr("uint", err)
+	}
+	return val, nil
 }
 
 func (item *Item) ToString() string {
@@ -35,34 +110,108 @@ func (item *Item) ToString() string {
 }
 
 func (item *Item) ToFloat() (float64, error) {
-	return strconv.ParseFloat(item.val, 64)
+	val, err := item.cached("float", "", func() (interface{}, error) {
+		val, err := parseFloat(item.val)
+		if err != nil {
+			return nil, item.typeErr("float", err)
+		}
+		return val, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return val.(float64), nil
+}
+
+// ToBool parses the value as 'true' or 'false', ignoring case, as bool
+// fields are loaded.
+func (item *Item) ToBool() (bool, error) {
+	val, ok := parseBool(item.val)
+	if !ok {
+		return false, item.typeErr("bool", nil)
+	}
+	return val, nil
+}
+
+// ToPercent parses a percentage like '75%' into a ratio 0.75. A plain
+// float is taken as a ratio already.
+func (item *Item) ToPercent() (float64, error) {
+	s, scale := item.val, 1.0
+	if strings.HasSuffix(s, _PERCENT) {
+		s, scale = strings.TrimRight(s[:len(s)-len(_PERCENT)], _SPACE_CHARS), 100
+	}
+
+	val, err := parseFloat(s)
+	if err != nil {
+		return 0, item.typeErr("percent", err)
+	}
+	return val / scale, nil
 }
 
 func (item *Item) ToIntArray() ([]int64, error) {
-	eleStr := item.ToStringArray()
+	return item.ToIntArraySep(string(item.elementSep()))
+}
 
-	values := make([]int64, len(eleStr))
-	for idx, ele := range eleStr {
-		ele = strings.Trim(ele, _SPACE_CHARS)
-		val, err := strconv.ParseInt(ele, 10, 64)
-		if err != nil {
-			return nil, goutils.WrapErr(err)
+// ToIntArraySep is like ToIntArray, but splits the value by sep. The array
+// is a copy of the cached one, so it may be changed.
+func (item *Item) ToIntArraySep(sep string) ([]int64, error) {
+	values, err := item.cached("int array", sep, func() (interface{}, error) {
+		eleStr := item.ToStringArraySep(sep)
+
+		values := make([]int64, len(eleStr))
+		for idx, ele := range eleStr {
+			ele = strings.Trim(ele, _SPACE_CHARS)
+			val, err := parseInt(ele)
+			if err != nil {
+				return nil, item.typeErr("int array", err)
+			}
+			values[idx] = val
 		}
-		values[idx] = val
+		return values, nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return values, nil
+	return slices.Clone(values.([]int64)), nil
 }
 
 func (item *Item) ToFloatArray() ([]float64, error) {
+	return item.ToFloatArraySep(string(item.elementSep()))
+}
+
+// ToFloatArraySep is like ToFloatArray, but splits the value by sep. The
+// array is a copy of the cached one, so it may be changed.
+func (item *Item) ToFloatArraySep(sep string) ([]float64, error) {
+	values, err := item.cached("float array", sep, func() (interface{}, error) {
+		eleStr := item.ToStringArraySep(sep)
+
+		values := make([]float64, len(eleStr))
+		for idx, ele := range eleStr {
+			ele = strings.Trim(ele, _SPACE_CHARS)
+			val, err := parseFloat(ele)
+			if err != nil {
+				return nil, item.typeErr("float array", err)
+			}
+			values[idx] = val
+		}
+		return values, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(values.([]float64)), nil
+}
+
+// ToBoolArray parses the elements as 'true' or 'false', ignoring case, as
+// bool fields are loaded.
+func (item *Item) ToBoolArray() ([]bool, error) {
 	eleStr := item.ToStringArray()
 
-	values := make([]float64, len(eleStr))
+	values := make([]bool, len(eleStr))
 	for idx, ele := range eleStr {
-		ele = strings.Trim(ele, _SPACE_CHARS)
-		val, err := strconv.ParseFloat(ele, 64)
-		if err != nil {
-			return nil, goutils.WrapErr(err)
+		val, ok := parseBool(ele)
+		if !ok {
+			return nil, item.typeErr("bool array", nil)
 		}
 		values[idx] = val
 	}
@@ -71,7 +220,13 @@ func (item *Item) ToFloatArray() ([]float64, error) {
 }
 
 func (item *Item) ToStringArray() []string {
-	parts := strings.Split(item.val, string(elementSep))
+	return item.ToStringArraySep(string(item.elementSep()))
+}
+
+// ToStringArraySep is like ToStringArray, but splits the value by sep, e.g.
+// ',' or '|', instead of the element separator.
+func (item *Item) ToStringArraySep(sep string) []string {
+	parts := strings.Split(item.val, sep)
 
 	var eles []string
 	for _, p := range parts {
@@ -82,3 +237,195 @@ func (item *Item) ToStringArray() []string {
 
 	return eles
 }
+
+// ToBytes decodes the value as base64, optionally prefixed by '@base64:'.
+// Spaces in the value are ignored, and padding is optional.
+func (item *Item) ToBytes() ([]byte, error) {
+	enc := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(_SPACE_CHARS, r) {
+			return -1
+		}
+		return r
+	}, strings.TrimPrefix(item.val, _BASE64_PREFIX))
+
+	val, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(enc, "="))
+	if err != nil {
+		return nil, item.typeErr("bytes", err)
+	}
+	return val, nil
+}
+
+// elementSep returns the separator of elements of the value.
+func (item *Item) elementSep() byte {
+	if item.sep == 0 {
+		return _DEFAULT_SEP
+	}
+	return item.sep
+}
+
+// parseInt parses a decimal int, or a hex, octal or binary one prefixed by
+// '0x', '0o' or '0b'. A leading '0' alone doesn't make it octal, so '010'
+// is 10.
+func parseInt(s string) (int64, error) {
+	s = stripDigitSep(s)
+	return strconv.ParseInt(s, intBase(s), 64)
+}
+
+func parseUint(s string) (uint64, error) {
+	s = stripDigitSep(s)
+	return strconv.ParseUint(s, intBase(s), 64)
+}
+
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(stripDigitSep(s), 64)
+}
+
+// stripDigitSep removes '_' separating digits, e.g. '1_000_000' is
+// '1000000'. s is kept if an '_' isn't between two digits, so it fails to
+// parse.
+func stripDigitSep(s string) string {
+	if strings.IndexByte(s, _DIGIT_SEP) < 0 {
+		return s
+	}
+
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] == _DIGIT_SEP && (idx == 0 || idx == len(s)-1 ||
+			!isHexDigit(s[idx-1]) || !isHexDigit(s[idx+1])) {
+			return s
+		}
+	}
+	return strings.ReplaceAll(s, string(_DIGIT_SEP), "")
+}
+
+// isHexDigit reports whether c is a digit of any radix, up to hex.
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// intBase returns 0, which makes strconv take the base from the prefix, if
+// s has a radix prefix, or 10 otherwise.
+func intBase(s string) int {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) > 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			return 0
+		}
+	}
+	return 10
+}
+
+// parseBool parses 'true' or 'false', ignoring case.
+func parseBool(s string) (val bool, ok bool) {
+	switch strings.ToLower(s) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+func (item *Item) typeErr(typ string, err error) error {
+	return &TypeError{Key: item.key, Val: item.val, Type: typ, Err: err}
+}
+
+// Types inferred from values, see InferType.
+const (
+	_TYPE_INT      = "int"
+	_TYPE_FLOAT    = "float"
+	_TYPE_BOOL     = "bool"
+	_TYPE_DURATION = "duration"
+	_TYPE_STRING   = "string"
+	_TYPE_ARRAY    = "array-of-"
+)
+
+// InferType infers the type of the value: "int", "float", "bool", "duration"
+// (e.g. '1m30s') or "string". A value which splits into several elements of
+// the same type is "array-of-" the type, e.g. "array-of-int". Arrays mixing
+// ints and floats are "array-of-float".
+func (item *Item) InferType() string {
+	if typ := inferScalar(item.val); typ != _TYPE_STRING {
+		return typ
+	}
+
+	eles := item.ToStringArray()
+	if len(eles) < 2 {
+		return _TYPE_STRING
+	}
+
+	typ := inferScalar(eles[0])
+	for _, ele := range eles[1:] {
+		eleType := inferScalar(ele)
+		if eleType == typ {
+			continue
+		}
+		if isNumberType(eleType) && isNumberType(typ) {
+			typ = _TYPE_FLOAT
+			continue
+		}
+		return _TYPE_STRING
+	}
+	if typ == _TYPE_STRING {
+		return _TYPE_STRING
+	}
+
+	return _TYPE_ARRAY + typ
+}
+
+// value converts the item to the type inferred by InferType. Durations are
+// kept as strings.
+func (item *Item) value() interface{} {
+	switch item.InferType() {
+	case _TYPE_INT:
+		val, _ := item.ToInt()
+		return val
+	case _TYPE_FLOAT:
+		val, _ := item.ToFloat()
+		return val
+	case _TYPE_BOOL:
+		return strings.ToLower(item.val) == "true"
+	case _TYPE_ARRAY + _TYPE_INT:
+		vals, _ := item.ToIntArray()
+		return vals
+	case _TYPE_ARRAY + _TYPE_FLOAT:
+		vals, _ := item.ToFloatArray()
+		return vals
+	case _TYPE_ARRAY + _TYPE_BOOL:
+		eles := item.ToStringArray()
+		vals := make([]bool, len(eles))
+		for idx, ele := range eles {
+			vals[idx] = strings.ToLower(ele) == "true"
+		}
+		return vals
+	case _TYPE_ARRAY + _TYPE_DURATION:
+		return item.ToStringArray()
+	}
+
+	return item.val
+}
+
+// inferScalar infers the type of one value. Only finite numbers are floats,
+// 'nan' and 'inf' are strings, as JSON has no such numbers.
+func inferScalar(s string) string {
+	if _, err := parseInt(s); err == nil {
+		return _TYPE_INT
+	}
+	if f, err := parseFloat(s); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		return _TYPE_FLOAT
+	}
+
+	lower := strings.ToLower(s)
+	if lower == "true" || lower == "false" {
+		return _TYPE_BOOL
+	}
+	if _, err := time.ParseDuration(s); err == nil {
+		return _TYPE_DURATION
+	}
+
+	return _TYPE_STRING
+}
+
+func isNumberType(typ string) bool {
+	return typ == _TYPE_INT || typ == _TYPE_FLOAT
+}
@@ -0,0 +1,128 @@
+/**
+ * JSON config files, and export of a parsed config as JSON.
+ *
+ *  A '.json' config file is a JSON object. Its members with object values
+ *  are sections, and the other members are global items. Arrays are array
+ *  items, whose elements are joined by the element separator. null members
+ *  are skipped.
+ *
+ *      e.g.
+ *          {"name": "app", "ports": [80, 443], "db": {"timeout": 1.5}}
+ *
+ *      is the same as config file:
+ *          > name: app
+ *          > ports: 80 443
+ *          > [db]
+ *          > timeout: 1.5
+ *
+ *  ToJSON does the reverse, values are converted to numbers, booleans and
+ *  arrays where possible.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 17:58:40
+ */
+
+package goconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/chosen0ne/goutils"
+	"io"
+)
+
+// ToJSON exports the conf as a JSON object. Global items are members of the
+// object, and each section is a nested object. Values are converted to
+// numbers, booleans and arrays where possible, and are strings otherwise.
+func (conf *Conf) ToJSON() ([]byte, error) {
+	if err := conf.loadSections(); err != nil {
+		return nil, err
+	}
+	obj := conf.sections[_GLOBAL].toMap()
+	for _, name := range conf.Sections(false) {
+		if _, ok := obj[name]; ok {
+			return nil, goutils.NewErr("section '%s' conflicts with a global item in JSON", name)
+		}
+		obj[name] = conf.sections[name].toMap()
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+
+	return data, nil
+}
+
+func (s *section) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, s.len())
+	for _, key := range s.keys {
+		m[key] = s.m[key].value()
+	}
+
+	return m
+}
+
+func init() {
+	RegisterFormat(".json", parseJSON)
+}
+
+// parseJSON reads a JSON config file from r by MergeMapInOrder, keeping the
+// order of the members.
+func parseJSON(conf *Conf, r io.Reader, path string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fileErr(path, err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return &ParseError{Code: E_JSON, File: path, Msg: "invalid JSON: " + err.Error(), Err: err}
+	}
+
+	var keys []string
+	if err := jsonKeys(json.NewDecoder(bytes.NewReader(data)), "", 0, &keys); err != nil {
+		return &ParseError{Code: E_JSON, File: path, Msg: "invalid JSON: " + err.Error(), Err: err}
+	}
+	return conf.MergeMapInOrder(obj, keys)
+}
+
+// jsonKeys reads a value from dec, and appends the paths of the members of
+// its top two levels to keys, in order of the document.
+func jsonKeys(dec *json.Decoder, prefix string, depth int, keys *[]string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	for dec.More() {
+		if delim == '[' {
+			// Elements of arrays are values of items
+			if err := jsonKeys(dec, prefix, 2, keys); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		if depth < 2 {
+			*keys = append(*keys, prefix+key)
+		}
+		if err := jsonKeys(dec, prefix+key+string(_PATH_SEP), depth+1, keys); err != nil {
+			return err
+		}
+	}
+	// The closing delimiter
+	_, err = dec.Token()
+	return err
+}
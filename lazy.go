@@ -0,0 +1,201 @@
+/**
+ * Lazy parsing of sections, see WithLazySections. Parse only indexes the
+ * sections of a file, and the items of a section are parsed when it's
+ * first read, so a tool reading one section of hundreds starts fast.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 13:52:36
+ */
+
+package goconf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// A pendingSection is a section indexed by Parse but not parsed yet.
+type pendingSection struct {
+	body        string // lines after the header
+	path        string
+	line        int // line of the header
+	annotations map[string]string
+	err         error // error of parsing body, returned again by later reads
+}
+
+// errEager is returned by indexSections for a file which must be parsed
+// eagerly.
+var errEager = errors.New("the file must be parsed eagerly")
+
+// lazy reports whether sections of the file are parsed lazily. Overlays
+// and the options changing all the items parse the file eagerly.
+func (conf *Conf) lazy(merge bool) bool {
+	o := conf.opts
+	return o.lazySections && !merge && o.envPrefix == nil && o.decryptor == nil &&
+		len(o.secretResolvers) == 0 && o.limits == (Limits{}) && !o.interpolation
+}
+
+// parseFileLazily reads the whole file f, whose lines are referred to by
+// the sections, and parses it lazily.
+func (conf *Conf) parseFileLazily(f *os.File) error {
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fileErr(conf.filePath, err)
+	}
+
+	// data isn't changed any more, so it's shared by the strings
+	return conf.parseLazily(unsafe.String(unsafe.SliceData(data), len(data)), conf.filePath)
+}
+
+// parseLazily parses the global items of s, the content of file 'path',
+// and indexes the other sections. A file with directives, conditional
+// sections, profiles or inheriting sections is parsed eagerly, as they
+// change or read other sections, and so is a file with lines ended by lone
+// '\r'.
+func (conf *Conf) parseLazily(s, path string) error {
+	s = strings.TrimPrefix(s, _BOM)
+	global, pending, err := conf.indexSections(s, path)
+	if err == errEager {
+		return conf.parseFrom(&stringReader{s: s}, path, false)
+	} else if err != nil {
+		return err
+	}
+
+	if err := conf.parseFrom(&stringReader{s: global}, path, false); err != nil {
+		return err
+	}
+	if conf.pending == nil {
+		conf.pending = make(map[string]*pendingSection, len(pending))
+	}
+	for name, p := range pending {
+		conf.sections[name] = newSection()
+		conf.annotateSection(name, p.annotations)
+		conf.pending[name] = p
+	}
+
+	return nil
+}
+
+// indexSections splits s by the section headers, skipping multi-line
+// values. It returns the lines of the global section, and the sections by
+// name.
+func (conf *Conf) indexSections(s, path string) (string, map[string]*pendingSection, error) {
+	r := stringReader{s: s}
+	pending := make(map[string]*pendingSection)
+	global := s
+	var cur *pendingSection
+	var start int // offset of the body of cur
+	var annotations map[string]string
+	var marker string // end of the multi-line value being skipped
+	continued := false
+	lineNo := 0
+	for {
+		off := r.off
+		line, err := r.ReadString(_NEWLINE)
+		if len(line) == 0 && err == io.EOF {
+			break
+		}
+		lineNo++
+
+		lineStr := strings.Trim(line, _SPACE_CHARS)
+		if strings.IndexByte(lineStr, _CR) >= 0 {
+			return "", nil, errEager
+		}
+		switch {
+		case marker != "":
+			if lineStr == marker {
+				marker = ""
+			}
+		case continued:
+			continued = endsWithEscape(lineStr)
+		case len(lineStr) == 0:
+			annotations = nil
+		case isAnnotation(lineStr):
+			annotations = addAnnotation(annotations, lineStr)
+		case lineStr[0] == _COMMENT_TAG:
+			annotations = nil
+		case lineStr[0] == _DIRECTIVE_TAG:
+			return "", nil, errEager
+		case isSection(lineStr):
+			name, parent := splitParent(strings.Trim(lineStr[1:len(lineStr)-1], _SPACE_CHARS))
+			if isCondition(name) || strings.HasPrefix(name, _PROFILE_PREFIX+string(_SECTION_PREFIX_SEP)) || parent != "" {
+				return "", nil, errEager
+			}
+			if _, ok := pending[name]; ok || conf.sections[name] != nil {
+				return "", nil, parseErr(path, lineNo, E_DUP_SECTION, "section '%s' already exist", name)
+			}
+
+			if cur == nil {
+				global = s[:off]
+			} else {
+				cur.body = s[start:off]
+			}
+			cur = &pendingSection{path: path, line: lineNo, annotations: annotations}
+			pending[name] = cur
+			start = r.off
+			annotations = nil
+		default:
+			annotations = nil
+			continued = endsWithEscape(lineStr)
+			if sep, sepLen, _ := conf.itemSep(lineStr); sep >= 0 {
+				marker, _ = heredocMarker(strings.Trim(lineStr[sep+sepLen:], _SPACE_CHARS))
+			}
+		}
+	}
+	if cur != nil {
+		cur.body = s[start:]
+	}
+
+	return global, pending, nil
+}
+
+// loadSection parses the items of section 'name' if it's pending. A
+// section failing to parse stays empty.
+func (conf *Conf) loadSection(name string) error {
+	p, ok := conf.pending[name]
+	if !ok {
+		return nil
+	} else if p.err != nil {
+		return p.err
+	}
+
+	sec, cur := conf.sections[name], conf.cur
+	conf.cur = sec
+	err := conf.parseFromLine(&stringReader{s: p.body}, p.path, p.line, false)
+	conf.cur = cur
+	if err != nil {
+		*sec = *newSection()
+		p.err = err
+		return err
+	}
+
+	delete(conf.pending, name)
+	return nil
+}
+
+// pathErr returns the error of parsing the section of path 'section.key',
+// if it failed.
+func (conf *Conf) pathErr(key string) error {
+	if dot := strings.IndexByte(key, _PATH_SEP); dot > 0 {
+		if p, ok := conf.pending[key[:dot]]; ok {
+			return p.err
+		}
+	}
+	return nil
+}
+
+// loadSections parses all the pending sections, for methods reading the
+// whole conf. It returns the first error.
+func (conf *Conf) loadSections() error {
+	var first error
+	for _, name := range sortedKeys(conf.pending) {
+		if err := conf.loadSection(name); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	return first
+}
@@ -0,0 +1,141 @@
+/**
+ * Limits of the input of a parser, see WithLimits. So an untrusted or
+ * corrupted config file fails with an E_LIMIT error instead of growing
+ * memory without bound.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 13:05:21
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/chosen0ne/goutils"
+)
+
+// Limits bounds what a config may contain. A zero field is no limit.
+type Limits struct {
+	MaxFileSize int64 // bytes of a file, each included file counts alone
+	MaxLineLen  int   // bytes of a line, without '\n'
+	MaxItems    int   // items of a conf, of all the files parsed
+	MaxSections int   // sections of a conf, besides the global one
+}
+
+// WithLimits makes parsing fail with an E_LIMIT error once the input
+// exceeds the limits.
+func WithLimits(l Limits) Option {
+	return func(o *options) {
+		o.limits = l
+	}
+}
+
+// limitedReader reads the lines of file 'path' from r while checking the
+// size of lines and of the file.
+type limitedReader struct {
+	r      lineReader
+	limits Limits
+	path   string
+	lineNo int
+	size   int64
+}
+
+func (lr *limitedReader) ReadString(delim byte) (string, error) {
+	lr.lineNo++
+	line, err := lr.r.ReadString(delim)
+	if err == bufio.ErrTooLong {
+		return "", lr.tooLong()
+	} else if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	lr.size += int64(len(line))
+	if lr.limits.MaxFileSize > 0 && lr.size > lr.limits.MaxFileSize {
+		return "", limitErr(lr.path, lr.lineNo, "file exceeds %d bytes", lr.limits.MaxFileSize)
+	}
+	if lr.limits.MaxLineLen > 0 && len(strings.TrimRight(line, "\r\n")) > lr.limits.MaxLineLen {
+		return "", limitErr(lr.path, lr.lineNo, "line exceeds %d bytes", lr.limits.MaxLineLen)
+	}
+
+	return line, err
+}
+
+// tooLong returns the error of a line longer than the scanner accepts,
+// by the smaller limit.
+func (lr *limitedReader) tooLong() error {
+	if max := lr.limits.MaxFileSize; max > 0 && (lr.limits.MaxLineLen == 0 || max < int64(lr.limits.MaxLineLen)) {
+		return limitErr(lr.path, lr.lineNo, "file exceeds %d bytes", max)
+	}
+	return limitErr(lr.path, lr.lineNo, "line exceeds %d bytes", lr.limits.MaxLineLen)
+}
+
+// maxLine returns the size of the longest line the limits accept, or 0 if
+// there's no limit of lines.
+func (l Limits) maxLine() int {
+	max := 0
+	if l.MaxLineLen > 0 {
+		max = l.MaxLineLen + len("\r\n")
+	}
+	if l.MaxFileSize > 0 && l.MaxFileSize < math.MaxInt32 && (max == 0 || int(l.MaxFileSize) < max) {
+		max = int(l.MaxFileSize) + 1
+	}
+	return max
+}
+
+// newLineReader returns the reader of lines parsed from buf: a *bufio.Reader
+// is scanned by blocks, lines are split by lone '\r', reading stops once the
+// context of ParseContext is done, and lines are checked by the limits of
+// conf, if any.
+func (conf *Conf) newLineReader(buf lineReader, path string) lineReader {
+	limits := conf.opts.limits
+	if br, ok := buf.(*bufio.Reader); ok {
+		buf = newBlockReader(br, limits.maxLine())
+	}
+	buf = &crReader{r: buf}
+	if conf.ctx != nil && conf.ctx.Done() != nil {
+		buf = &ctxReader{r: buf, ctx: conf.ctx}
+	}
+	if limits == (Limits{}) {
+		return buf
+	}
+	return &limitedReader{r: buf, limits: limits, path: path}
+}
+
+// checkSections fails if conf has more sections than the limit.
+func (conf *Conf) checkSections(path string, lineNo int) error {
+	if max := conf.opts.limits.MaxSections; max > 0 && len(conf.sections)-1 > max {
+		return limitErr(path, lineNo, "more than %d sections", max)
+	}
+	return nil
+}
+
+// countItem counts a new item, and fails if conf has more items than the
+// limit. Items are only counted if there's a limit.
+func (conf *Conf) countItem(path string, lineNo int) error {
+	conf.itemCount++
+	if max := conf.opts.limits.MaxItems; max > 0 && conf.itemCount > max {
+		return limitErr(path, lineNo, "more than %d items", max)
+	}
+	return nil
+}
+
+func limitErr(path string, lineNo int, format string, args ...interface{}) error {
+	return parseErr(path, lineNo, E_LIMIT, format, args...)
+}
+
+// readErr returns an error of reading a config. Errors of limits and of
+// the context of ParseContext are returned as they are, so they can be
+// told by ErrorCode and errors.Is.
+func readErr(err error) error {
+	if _, ok := err.(*ParseError); ok {
+		return err
+	} else if err == context.Canceled || err == context.DeadlineExceeded {
+		return err
+	}
+	return goutils.WrapErr(err)
+}
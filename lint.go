@@ -0,0 +1,181 @@
+/**
+ * Lint checks a parsed config against house rules, e.g. naming conventions,
+ * forbidden values or required annotations. Rules are plugins: anything
+ * implementing Rule can be run along with the built-in ones.
+ *
+ *      e.g.
+ *          linter := NewLinter(
+ *              KeyPattern(regexp.MustCompile(`^[a-z][a-z0-9_]*$`)),
+ *              ForbiddenValues("db.password", "changeme", "123456"),
+ *              RequiredAnnotation("owner"),
+ *          )
+ *          diags := linter.Run(conf)
+ *          for _, d := range diags {
+ *              fmt.Println(d)
+ *          }
+ *          if HasErrors(diags) {
+ *              os.Exit(1)
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 23:10:44
+ */
+
+package goconf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "unknown"
+}
+
+// A Diagnostic is a problem found by a rule, in an item, or in a section if
+// Key is empty.
+type Diagnostic struct {
+	Rule     string // name of the rule
+	Severity Severity
+	Section  string // GlobalSection for global items
+	Key      string
+	Msg      string
+}
+
+func (d Diagnostic) String() string {
+	where := d.Section
+	if d.Key != "" {
+		where = itemPath(d.Section, d.Key)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", d.Severity, where, d.Msg, d.Rule)
+}
+
+// A Rule checks a conf, and returns the problems found.
+type Rule interface {
+	Check(conf *Conf) []Diagnostic
+}
+
+// RuleFunc adapts a function to a Rule.
+type RuleFunc func(conf *Conf) []Diagnostic
+
+func (f RuleFunc) Check(conf *Conf) []Diagnostic {
+	return f(conf)
+}
+
+// A Linter runs a set of rules.
+type Linter struct {
+	rules []Rule
+}
+
+func NewLinter(rules ...Rule) *Linter {
+	return &Linter{rules}
+}
+
+// Add adds rules to the linter.
+func (l *Linter) Add(rules ...Rule) *Linter {
+	l.rules = append(l.rules, rules...)
+	return l
+}
+
+// Run runs all the rules on conf, and returns the diagnostics ordered by
+// section and key, the global section first.
+func (l *Linter) Run(conf *Conf) []Diagnostic {
+	var diags []Diagnostic
+	for _, rule := range l.rules {
+		diags = append(diags, rule.Check(conf)...)
+	}
+
+	sort.SliceStable(diags, func(i, j int) bool {
+		a, b := diags[i], diags[j]
+		if a.Section != b.Section {
+			return a.Section == _GLOBAL || (b.Section != _GLOBAL && a.Section < b.Section)
+		}
+		return a.Key < b.Key
+	})
+
+	return diags
+}
+
+// HasErrors reports whether any of diags is an error, e.g. to fail a CI job.
+func HasErrors(diags []Diagnostic) bool {
+	for _, d := range diags {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ------- Rules ------- //
+
+// KeyPattern warns of the keys of items and the names of sections which
+// don't match re.
+func KeyPattern(re *regexp.Regexp) Rule {
+	return RuleFunc(func(conf *Conf) []Diagnostic {
+		var diags []Diagnostic
+		for _, name := range conf.Sections(false) {
+			if !re.MatchString(name) {
+				diags = append(diags, Diagnostic{Rule: "key-pattern", Severity: SeverityWarning,
+					Section: name, Msg: fmt.Sprintf("section name doesn't match '%s'", re)})
+			}
+		}
+		conf.Walk(func(section string, item *Item) error {
+			if !re.MatchString(item.key) {
+				diags = append(diags, Diagnostic{Rule: "key-pattern", Severity: SeverityWarning,
+					Section: section, Key: item.key, Msg: fmt.Sprintf("key doesn't match '%s'", re)})
+			}
+			return nil
+		})
+		return diags
+	})
+}
+
+// ForbiddenValues reports item 'key', a global item or a path like
+// 'db.password', if its value is one of vals.
+func ForbiddenValues(key string, vals ...string) Rule {
+	return RuleFunc(func(conf *Conf) []Diagnostic {
+		var diags []Diagnostic
+		conf.Walk(func(section string, item *Item) error {
+			if itemPath(section, item.key) != key {
+				return nil
+			}
+			for _, val := range vals {
+				if item.val == val {
+					diags = append(diags, Diagnostic{Rule: "forbidden-value", Severity: SeverityError,
+						Section: section, Key: item.key, Msg: fmt.Sprintf("value '%s' is forbidden", val)})
+				}
+			}
+			return nil
+		})
+		return diags
+	})
+}
+
+// RequiredAnnotation reports the sections without annotation 'name', see
+// SectionAnnotations.
+func RequiredAnnotation(name string) Rule {
+	return RuleFunc(func(conf *Conf) []Diagnostic {
+		var diags []Diagnostic
+		for _, section := range conf.Sections(false) {
+			if _, ok := conf.annotations[section][name]; !ok {
+				diags = append(diags, Diagnostic{Rule: "required-annotation", Severity: SeverityError,
+					Section: section, Msg: fmt.Sprintf("missing annotation '@%s'", name)})
+			}
+		}
+		return diags
+	})
+}
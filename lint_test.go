@@ -0,0 +1,51 @@
+/**
+ * Unit test cases for Linter
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 23:24:05
+ */
+
+package goconf
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	conf, buf := genConf("appName: x\n#@owner: infra\n[db]\npassword: changeme\n[Cache]\nsize: 10\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	custom := RuleFunc(func(conf *Conf) []Diagnostic {
+		if v, err := conf.GetIntFrom("Cache", "size"); err == nil && v < 100 {
+			return []Diagnostic{{Rule: "cache-size", Severity: SeverityWarning,
+				Section: "Cache", Key: "size", Msg: "too small"}}
+		}
+		return nil
+	})
+	linter := NewLinter(
+		KeyPattern(regexp.MustCompile(`^[a-z_]+$`)),
+		ForbiddenValues("db.password", "changeme"),
+	).Add(RequiredAnnotation("owner"), custom)
+
+	expected := []string{
+		"warning: appName: key doesn't match '^[a-z_]+$' [key-pattern]",
+		"warning: Cache: section name doesn't match '^[a-z_]+$' [key-pattern]",
+		"error: Cache: missing annotation '@owner' [required-annotation]",
+		"warning: Cache.size: too small [cache-size]",
+		"error: db.password: value 'changeme' is forbidden [forbidden-value]",
+	}
+	diags := linter.Run(conf)
+	output := make([]string, len(diags))
+	for idx, d := range diags {
+		output[idx] = d.String()
+	}
+	if err := matchStringArray(output, expected); err != nil {
+		t.Errorf("not expected diagnostics, err: %s, output: %v", err, output)
+	}
+	if !HasErrors(diags) || HasErrors(diags[:1]) {
+		t.Errorf("HasErrors mismatch")
+	}
+}
@@ -46,6 +46,9 @@
  *          3. 'aexamplefield'
  *          4. 'AExampleField'
  *
+ *      A field without config item keeps its value, unless 'LoadStrict' is
+ *      used. Then only fields tagged by `goconf:"optional"` may be missing.
+ *
  * @author  chosen0ne(louzhenlin86@126.com
  * @date    2014/11/05 11:50:13
  */
@@ -53,6 +56,7 @@
 package goconf
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"github.com/chosen0ne/goutils"
@@ -60,98 +64,307 @@ import (
 	"strings"
 )
 
+const (
+	_TAG          = "goconf"
+	_TAG_OPTIONAL = "optional"
+)
+
 // Load will set the config object by a file.
-func Load(configObjPtr interface{}, configFile string) error {
+func Load(configObjPtr interface{}, configFile string, opts ...Option) error {
+	mergeOpts, err := mergeOptions(configObjPtr, "")
+	if err != nil {
+		return err
+	}
+
+	// Create and Parse conf
+	conf := New(configFile, append(mergeOpts, opts...)...)
+
+	if err := conf.Parse(); err != nil {
+		return err
+	}
+
+	return loadConf(configObjPtr, conf, opts)
+}
+
+// LoadWithDefaults is like Load, but parses embeddedDefault before the file.
+// The file is an overlay of the defaults: its items override the default
+// items, and its sections are merged into the default sections. So a binary
+// can carry a complete default config, e.g. by go:embed, and the file only
+// needs the deltas.
+func LoadWithDefaults(
+	configObjPtr interface{},
+	embeddedDefault []byte,
+	configFile string,
+	opts ...Option) error {
+	mergeOpts, err := mergeOptions(configObjPtr, "")
+	if err != nil {
+		return err
+	}
+
+	conf := New(configFile, append(mergeOpts, opts...)...)
+
+	buf := bufio.NewReader(bytes.NewReader(embeddedDefault))
+	if err := conf.parseFrom(buf, "", false); err != nil {
+		return err
+	}
+
+	if err := conf.parseFile(true); err != nil {
+		return err
+	}
+
+	return loadConf(configObjPtr, conf, opts)
+}
+
+// LoadStrict is like Load, but every field must be matched by a config
+// item or section, unless it's tagged by `goconf:"optional"`. All the
+// unmatched fields are reported by a *MissingFieldsError.
+func LoadStrict(configObjPtr interface{}, configFile string, opts ...Option) error {
+	return Load(configObjPtr, configFile, append(opts, RequireAllFields())...)
+}
+
+// LoadSection is like Load, but only loads section 'sectionName' into the
+// config object, so a component can fill its own struct from a shared
+// config file. Fields are mapped to the items of the section, and the
+// other sections are ignored, even by DisallowUnknownFields.
+func LoadSection(configObjPtr interface{}, configFile, sectionName string, opts ...Option) error {
+	mergeOpts, err := mergeOptions(configObjPtr, sectionName)
+	if err != nil {
+		return err
+	}
+
+	conf := New(configFile, append(mergeOpts, opts...)...)
+
+	if err := conf.Parse(); err != nil {
+		return err
+	}
+
+	return loadConfSection(configObjPtr, conf, sectionName, opts)
+}
+
+// LoadFromConf loads the config object from a parsed conf, so a file
+// parsed once can be loaded into several objects, or loaded again after
+// it's reloaded. The options are the ones of loading, e.g.
+// DisallowUnknownKeys, as the conf is already parsed.
+func LoadFromConf(configObjPtr interface{}, conf *Conf, opts ...Option) error {
+	return loadConf(configObjPtr, conf, opts)
+}
+
+// loader keeps the state of loading a config object from a conf. It only
+// reads the conf, so a parsed conf can be loaded by several goroutines,
+// except a conf with pending sections, see WithLazySections.
+type loader struct {
+	conf    *Conf
+	opts    *options
+	sec     *section       // section of the struct being loaded
+	used    map[*Item]bool // items consumed by fields
+	prefix  string         // path of the struct being loaded, e.g. 'Section1.'
+	missing []string       // fields without config items
+}
+
+func loadConf(configObjPtr interface{}, conf *Conf, opts []Option) error {
+	return loadConfSection(configObjPtr, conf, _GLOBAL, opts)
+}
+
+// loadConfSection loads the config object from section 'sectionName' of
+// conf. Sections of struct fields are looked up by name as usual.
+func loadConfSection(configObjPtr interface{}, conf *Conf, sectionName string, opts []Option) error {
 	// Settable?
 	configObj := reflect.ValueOf(configObjPtr).Elem()
 	if !configObj.CanSet() {
 		return errors.New("configObj must be settable")
 	}
 
-	// Create and Parse conf
-	conf := New(configFile)
-
-	if err := conf.Parse(); err != nil {
+	// The whole conf is read by the global section, as sections are
+	// loaded into fields and unknown keys are checked
+	if sectionName == _GLOBAL {
+		if err := conf.loadSections(); err != nil {
+			return err
+		}
+	} else if err := conf.loadSection(sectionName); err != nil {
 		return err
 	}
 
+	sec, ok := conf.sections[sectionName]
+	if !ok {
+		return sectionNotFound(sectionName)
+	}
+
+	l := &loader{
+		conf: conf,
+		opts: newOptions(opts),
+		sec:  sec,
+		used: make(map[*Item]bool),
+	}
+
 	// Load fields from conf
 	t := configObj.Type()
 	for i := 0; i < configObj.NumField(); i++ {
 		fieldValue := configObj.Field(i)
 		fieldMeta := t.Field(i)
-		if err := loadField(&fieldMeta, &fieldValue, conf); err != nil {
+		if err := l.loadField(&fieldMeta, &fieldValue); err != nil {
 			return err
 		}
 	}
 
+	if len(l.missing) != 0 {
+		return &MissingFieldsError{l.missing}
+	}
+
+	return l.checkUnknown(sectionName)
+}
+
+// checkUnknown reports the items which no field consumed, as the options
+// ask. Only the items of section 'only' are checked, unless it's the global
+// section. Warnings of the load are returned by CollectWarnings, and the
+// conf isn't changed, as it may be loaded concurrently.
+func (l *loader) checkUnknown(only string) error {
+	var unknown []string
+	var warnings []Warning
+	if l.opts.warnings != nil {
+		warnings = l.conf.Warnings()
+	}
+	l.conf.Walk(func(section string, item *Item) error {
+		if l.used[item] || (only != _GLOBAL && section != only) {
+			return nil
+		}
+		name := itemPath(section, item.key)
+		warnings = append(warnings, Warning{Source: item.origin.String(), Key: name, Msg: "unknown key, no field consumes it"})
+		unknown = append(unknown, name)
+		return nil
+	})
+	if l.opts.warnings != nil {
+		*l.opts.warnings = warnings
+	}
+
+	if l.opts.unknownKeys != nil {
+		*l.opts.unknownKeys = unknown
+	}
+	if l.opts.disallowUnknown && len(unknown) != 0 {
+		return &UnknownKeysError{unknown}
+	}
+
 	return nil
 }
 
-func loadField(
+// has reports whether there's an item 'name' in the section being loaded,
+// or a section 'name'.
+func (l *loader) has(name string) bool {
+	_, ok := l.conf.lookup(l.sec, name)
+	if !ok {
+		_, ok = l.conf.section(name)
+	}
+	return ok
+}
+
+func (l *loader) loadField(
 	fieldMeta *reflect.StructField,
-	fieldValue *reflect.Value,
-	conf *Conf) error {
+	fieldValue *reflect.Value) error {
 	fieldName := fieldMeta.Name
 	// Check field settable?
 	if !fieldValue.CanSet() {
 		return errors.New("field not settable, field: " + fieldName)
 	}
 
-	optName, err := parseConfigOptName(fieldName, conf)
+	if isSectionMap(fieldValue.Type()) {
+		return l.loadSectionMap(fieldMeta, fieldValue)
+	}
+
+	optName, err := parseConfigOptName(fieldName, l.has)
 	if err != nil {
 		// no config option mapped to the field.
 		// just return, and field can be set by a default value
+		if l.opts.requireAll && !hasTagOpt(fieldMeta, _TAG_OPTIONAL) {
+			l.missing = append(l.missing, l.prefix+fieldName)
+		}
 		return nil
 	}
 
+	if l.conf.HasSection(optName) {
+		if u := implementer(fieldValue, sectionUnmarshalerType); u != nil {
+			return l.unmarshalSection(u.(SectionUnmarshaler), optName)
+		}
+	}
+
 	// Fetch value from conf, and load Config Object
 	kind := fieldValue.Kind()
-	if isInt(kind) {
-		val, err := conf.GetInt(optName)
+	conv := converterOf(fieldValue.Type())
+	unmarshaler := implementer(fieldValue, unmarshalerType)
+	if kind == reflect.Struct && conv == nil && unmarshaler == nil {
+		return l.loadStruct(fieldName, optName, fieldValue)
+	}
+
+	item, ok := l.conf.lookup(l.sec, optName)
+	if l.conf.recording != nil {
+		l.conf.record(l.conf.sectionName(l.sec), optName, item)
+	}
+	if !ok {
+		return keyNotFound(optName)
+	}
+	l.used[item] = true
+
+	return l.setValue(optName, item, fieldValue)
+}
+
+// setValue sets a field of any type but struct by item, which is named
+// 'name' in errors.
+func (l *loader) setValue(name string, item *Item, fieldValue *reflect.Value) error {
+	kind := fieldValue.Kind()
+	if unmarshaler := implementer(fieldValue, unmarshalerType); unmarshaler != nil {
+		return unmarshaler.(Unmarshaler).UnmarshalConf(item)
+	} else if kind == reflect.Slice && converterOf(fieldValue.Type()) == nil {
+		return loadSliceField(name, item, fieldValue)
+	} else if kind == reflect.Map {
+		return l.loadMapField(name, item, fieldValue)
+	}
+
+	return setScalar(name, item, fieldValue)
+}
+
+// setScalar sets a field of a scalar or converted type by item, which is
+// named 'name' in errors.
+func setScalar(name string, item *Item, fieldValue *reflect.Value) error {
+	kind := fieldValue.Kind()
+	if conv := converterOf(fieldValue.Type()); conv != nil {
+		val, err := conv(item)
 		if err != nil {
 			return err
 		}
-		fieldValue.SetInt(val)
-	} else if kind == reflect.Float32 || kind == reflect.Float64 {
-		val, err := conf.GetFloat(optName)
+		fieldValue.Set(reflect.ValueOf(val))
+	} else if isUint(kind) {
+		val, err := item.ToUint()
 		if err != nil {
 			return err
 		}
-		fieldValue.SetFloat(val)
-	} else if kind == reflect.Bool {
-		val, err := conf.GetString(optName)
+		if fieldValue.OverflowUint(val) {
+			return &TypeError{Key: name, Val: item.val, Type: "uint", Err: rangeErr(kind)}
+		}
+		fieldValue.SetUint(val)
+	} else if isInt(kind) {
+		val, err := item.ToInt()
 		if err != nil {
 			return err
 		}
-		lowerVal := strings.ToLower(val)
-		if lowerVal != "true" && lowerVal != "false" {
-			return goutils.NewErr("bool config option must be 'True' of 'False'")
+		if fieldValue.OverflowInt(val) {
+			return &TypeError{Key: name, Val: item.val, Type: "int", Err: rangeErr(kind)}
 		}
-		fieldValue.SetBool("true" == lowerVal)
-	} else if kind == reflect.String {
-		val, err := conf.GetString(optName)
+		fieldValue.SetInt(val)
+	} else if kind == reflect.Float32 || kind == reflect.Float64 {
+		val, err := item.ToFloat()
 		if err != nil {
 			return err
 		}
-		fieldValue.SetString(val)
-	} else if kind == reflect.Slice {
-		if err := loadSliceField(fieldMeta, optName, fieldValue, conf); err != nil {
-			return err
+		if fieldValue.OverflowFloat(val) {
+			return &TypeError{Key: name, Val: item.val, Type: "float", Err: rangeErr(kind)}
 		}
-	} else if kind == reflect.Struct {
-		conf.Section(optName)
-		innerFieldType := fieldValue.Type()
-		for j := 0; j < fieldValue.NumField(); j++ {
-			innerFieldVal := fieldValue.Field(j)
-			innerFieldMeta := innerFieldType.Field(j)
-			if err := loadField(&innerFieldMeta, &innerFieldVal, conf); err != nil {
-				return err
-			}
+		fieldValue.SetFloat(val)
+	} else if kind == reflect.Bool {
+		val, ok := parseBool(item.val)
+		if !ok {
+			return &TypeError{Key: name, Val: item.val, Type: "bool"}
 		}
-
-		// recover to use global section
-		conf.SetGlobalSection()
+		fieldValue.SetBool(val)
+	} else if kind == reflect.String {
+		fieldValue.SetString(item.val)
 	} else {
 		return errors.New("not support type: " + kind.String())
 	}
@@ -159,46 +372,133 @@ func loadField(
 	return nil
 }
 
-func loadSliceField(
-	fieldMeta *reflect.StructField,
-	optName string,
-	fieldValue *reflect.Value,
-	conf *Conf) error {
+// loadStruct loads a struct field from section 'name'.
+func (l *loader) loadStruct(fieldName, name string, fieldValue *reflect.Value) error {
+	if err := l.conf.loadSection(name); err != nil {
+		return err
+	}
+	sec, ok := l.conf.section(name)
+	if !ok {
+		return sectionNotFound(name)
+	}
 
-	eleValue := fieldMeta.Type.Elem()
-	eleKind := eleValue.Kind()
+	outerSec, outerPrefix := l.sec, l.prefix
+	l.sec, l.prefix = sec, l.prefix+fieldName+"."
+	defer func() {
+		l.sec, l.prefix = outerSec, outerPrefix
+	}()
 
-	if isInt(eleKind) {
-		vals, err := conf.GetIntArray(optName)
-		if err != nil {
+	innerFieldType := fieldValue.Type()
+	for j := 0; j < fieldValue.NumField(); j++ {
+		innerFieldVal := fieldValue.Field(j)
+		innerFieldMeta := innerFieldType.Field(j)
+		if err := l.loadField(&innerFieldMeta, &innerFieldVal); err != nil {
 			return err
 		}
-		for _, val := range vals {
-			fieldValue.Set(reflect.Append(*fieldValue, reflect.ValueOf(val)))
-		}
-	} else if eleKind == reflect.Float32 || eleKind == reflect.Float64 {
-		vals, err := conf.GetFloatArray(optName)
+	}
+
+	return nil
+}
+
+// loadSliceField loads a slice field from an array item. Elements are
+// converted to the element type of the field, e.g. int32 for []int32, as
+// scalar fields are.
+func loadSliceField(name string, item *Item, fieldValue *reflect.Value) error {
+	eleType := fieldValue.Type().Elem()
+	eleKind := eleType.Kind()
+	conv := converterOf(eleType)
+
+	if eleKind == reflect.Uint8 && conv == nil {
+		val, err := item.ToBytes()
 		if err != nil {
 			return err
 		}
-		for _, val := range vals {
-			fieldValue.Set(reflect.Append(*fieldValue, reflect.ValueOf(val)))
+		fieldValue.SetBytes(val)
+		return nil
+	} else if eleKind == reflect.Slice && conv == nil {
+		return loadMatrixField(eleType, item, fieldValue)
+	}
+
+	// A new slice replaces the value of the field, e.g. of an earlier load
+	eles := item.ToStringArray()
+	vals := reflect.MakeSlice(fieldValue.Type(), 0, len(eles))
+	for _, ele := range eles {
+		eleValue := reflect.New(eleType).Elem()
+		if err := setScalar(name, &Item{key: item.key, val: ele}, &eleValue); err != nil {
+			var typeErr *TypeError
+			if errors.As(err, &typeErr) {
+				// reported as an error of the array
+				return &TypeError{Key: name, Val: item.val, Type: typeErr.Type + " array", Err: typeErr.Err}
+			}
+			return errors.New("not support element type for slice: " + eleType.String())
 		}
-	} else if eleKind == reflect.String {
-		vals, err := conf.GetStringArray(optName)
+		vals = reflect.Append(vals, eleValue)
+	}
+	fieldValue.Set(vals)
+
+	return nil
+}
+
+// loadMatrixField loads a field of a slice of rowType, e.g. [][]int64, from
+// a matrix item.
+func loadMatrixField(rowType reflect.Type, item *Item, fieldValue *reflect.Value) error {
+	var matrix reflect.Value
+	colType := rowType.Elem()
+	if colKind := colType.Kind(); isInt(colKind) {
+		vals, err := item.ToIntMatrix()
 		if err != nil {
 			return err
 		}
-		for _, val := range vals {
-			fieldValue.Set(reflect.Append(*fieldValue, reflect.ValueOf(val)))
+		matrix = reflect.ValueOf(vals)
+	} else if colKind == reflect.Float32 || colKind == reflect.Float64 {
+		vals, err := item.ToFloatMatrix()
+		if err != nil {
+			return err
 		}
+		matrix = reflect.ValueOf(vals)
+	} else if colKind == reflect.String {
+		matrix = reflect.ValueOf(item.ToStringMatrix())
 	} else {
-		return errors.New("not support element type for slice")
+		return errors.New("not support element type for matrix")
+	}
+
+	// Cells are converted to the type of the field, e.g. int64 to int
+	rows := reflect.MakeSlice(fieldValue.Type(), matrix.Len(), matrix.Len())
+	for i := 0; i < matrix.Len(); i++ {
+		cols := matrix.Index(i)
+		row := reflect.MakeSlice(rowType, cols.Len(), cols.Len())
+		for j := 0; j < cols.Len(); j++ {
+			row.Index(j).Set(cols.Index(j).Convert(colType))
+		}
+		rows.Index(i).Set(row)
 	}
+	fieldValue.Set(rows)
 
 	return nil
 }
 
+// hasTagOpt reports whether the `goconf` tag of field has option opt,
+// e.g. `goconf:"optional"`.
+func hasTagOpt(field *reflect.StructField, opt string) bool {
+	for _, o := range strings.Split(field.Tag.Get(_TAG), ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isUint(k reflect.Kind) bool {
+	return k == reflect.Uint || k == reflect.Uint8 || k == reflect.Uint16 ||
+		k == reflect.Uint32 || k == reflect.Uint64 || k == reflect.Uintptr
+}
+
+// rangeErr reports a value out of the range of a field of kind k.
+func rangeErr(k reflect.Kind) error {
+	return errors.New("out of the range of " + k.String())
+}
+
 func isInt(k reflect.Kind) bool {
 	if k == reflect.Int || k == reflect.Int8 || k == reflect.Int16 ||
 		k == reflect.Int32 || k == reflect.Int64 || k == reflect.Uint ||
@@ -216,37 +516,39 @@ func isInt(k reflect.Kind) bool {
 //      2. a_example_field
 //      3. aexamplefield
 //      4. AExampleField
-func parseConfigOptName(field string, conf *Conf) (string, error) {
-	// 1. a-example-field
-	f, err := upperToLower(field, '-')
-	if err != nil {
-		return "", err
-	}
-	if conf.HasItem(f) || conf.HasSection(f) {
-		return f, nil
+func parseConfigOptName(field string, has func(string) bool) (string, error) {
+	for _, f := range optNameCandidates(field) {
+		if has(f) {
+			return f, nil
+		}
 	}
 
-	// 2. a_example_field
-	f, err = upperToLower(field, '_')
-	if err != nil {
-		return "", err
-	}
-	if conf.HasItem(f) || conf.HasSection(f) {
-		return f, nil
-	}
+	return "", goutils.NewErr("new config option for %s", field)
+}
 
-	// 3. aexamplefield
-	f = strings.ToLower(field)
-	if conf.HasItem(f) || conf.HasSection(f) {
-		return f, nil
-	}
+// optNameCandidates returns the names of config options which field may
+// be mapped to, in order of search.
+func optNameCandidates(field string) []string {
+	dashed, _ := upperToLower(field, '-')
+	underscored, _ := upperToLower(field, '_')
+	return []string{dashed, underscored, strings.ToLower(field), field}
+}
 
-	// 4. AExampleField
-	if conf.HasItem(field) || conf.HasSection(field) {
-		return field, nil
+// keyName returns the key written for field, which is one of the names
+// it's mapped to: 'a_example_field', or 'dbhost' for 'DBHost' as an
+// acronym can't be split into words.
+func keyName(field string) string {
+	names := optNameCandidates(field)
+	for i := 1; i < len(field); i++ {
+		if isUpper(field[i-1]) && isUpper(field[i]) {
+			return names[2]
+		}
 	}
+	return names[1]
+}
 
-	return "", goutils.NewErr("new config option for %s", field)
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
 }
 
 func upperToLower(field string, sep byte) (string, error) {
@@ -0,0 +1,46 @@
+/**
+ * Logging of the events of a conf, so overrides and reloads can be traced
+ * in the logs of the application, e.g.
+ *
+ *      conf.SetLogger(log.New(os.Stderr, "goconf: ", log.LstdFlags))
+ *
+ *      logs 'app.conf:12: 'db.port' set at app.conf:3 is set again, by replace'.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 16:48:05
+ */
+
+package goconf
+
+// A Logger logs the events of a conf. *log.Logger is a Logger, and other
+// logging frameworks are adapted by a Printf method.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// SetLogger logs the events of conf by l: items set again while parsing,
+// e.g. by a repeated key or an included file, items overridden by a
+// profile, a conditional section or an env variable, and reloads. Nothing
+// is logged if l is nil, which is the default.
+func (conf *Conf) SetLogger(l Logger) {
+	conf.logger = l
+}
+
+func (conf *Conf) logf(format string, v ...interface{}) {
+	if conf.logger != nil {
+		conf.logger.Printf(format, v...)
+	}
+}
+
+// logSetAgain logs item 'name' set again at path:line, where old is the
+// item set before. duplicate reports whether the key is repeated in a file.
+func (conf *Conf) logSetAgain(name string, old *Item, path string, line int, duplicate bool) {
+	policy := MergeReplace.String()
+	if duplicate {
+		policy = conf.opts.duplicateKeys.String()
+	} else if s, ok := conf.opts.mergeStrategies[name]; ok {
+		policy = s.String()
+	}
+
+	conf.logf("%s:%d: '%s' set at %s is set again, by %s", path, line, name, old.Source(), policy)
+}
@@ -0,0 +1,101 @@
+/**
+ * A matrix is a two-level array, e.g. a weight table. Its rows are
+ * separated by the element separator of the item, and its columns by
+ * spaces.
+ *
+ *      e.g.
+ *          > [@weights@;]: 1 2 3; 4 5 6
+ *
+ *          conf.GetIntMatrix("weights") => [[1 2 3] [4 5 6]]
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 08:12:40
+ */
+
+package goconf
+
+import (
+	"strings"
+)
+
+// _MATRIX_ROW_SEP separates the rows of matrices set by FromStruct.
+const _MATRIX_ROW_SEP = ';'
+
+// matrixRows is a matrix value read by MergeMap, whose rows are columns
+// joined by spaces. It's set with _MATRIX_ROW_SEP as the element separator.
+type matrixRows []string
+
+// ToStringMatrix splits the value into rows by the element separator, and
+// the rows into columns by spaces. Empty rows are skipped.
+func (item *Item) ToStringMatrix() [][]string {
+	var rows [][]string
+	for _, row := range item.ToStringArray() {
+		if cols := strings.Fields(row); len(cols) != 0 {
+			rows = append(rows, cols)
+		}
+	}
+
+	return rows
+}
+
+func (item *Item) ToIntMatrix() ([][]int64, error) {
+	rows := item.ToStringMatrix()
+	values := make([][]int64, len(rows))
+	for i, row := range rows {
+		values[i] = make([]int64, len(row))
+		for j, col := range row {
+			val, err := parseInt(col)
+			if err != nil {
+				return nil, item.typeErr("int matrix", err)
+			}
+			values[i][j] = val
+		}
+	}
+
+	return values, nil
+}
+
+func (item *Item) ToFloatMatrix() ([][]float64, error) {
+	rows := item.ToStringMatrix()
+	values := make([][]float64, len(rows))
+	for i, row := range rows {
+		values[i] = make([]float64, len(row))
+		for j, col := range row {
+			val, err := parseFloat(col)
+			if err != nil {
+				return nil, item.typeErr("float matrix", err)
+			}
+			values[i][j] = val
+		}
+	}
+
+	return values, nil
+}
+
+// GetStringMatrix returns item 'key' as a matrix, see Item.ToStringMatrix.
+func (conf *Conf) GetStringMatrix(key string) ([][]string, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToStringMatrix(), nil
+}
+
+func (conf *Conf) GetIntMatrix(key string) ([][]int64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToIntMatrix()
+}
+
+func (conf *Conf) GetFloatMatrix(key string) ([][]float64, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToFloatMatrix()
+}
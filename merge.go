@@ -0,0 +1,255 @@
+/**
+ * Merge strategies decide the value of an item which is set again by a
+ * later layer, e.g. an included file, the file of LoadWithDefaults, or a
+ * repeated key. By default the later value replaces the former one, and a
+ * strategy can be declared per item by WithMergeStrategy, a Schema, or the
+ * tag of a field:
+ *
+ *      e.g.
+ *          type Config struct {
+ *              AllowedHosts []string `goconf:"merge=union"`
+ *              MaxConns     int      `goconf:"merge=min"`
+ *          }
+ *
+ *      with defaults 'allowed_hosts: a b' and 'max_conns: 100', and file
+ *      'allowed_hosts: b c' and 'max_conns: 200', the config has
+ *      'allowed_hosts: a b c' and 'max_conns: 100'.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 03:41:09
+ */
+
+package goconf
+
+import (
+	"github.com/chosen0ne/goutils"
+	"reflect"
+	"strings"
+)
+
+type MergeStrategy int
+
+const (
+	MergeReplace MergeStrategy = iota // the later value wins
+	MergeAppend                       // elements of the later value are appended
+	MergeUnion                        // elements not present yet are appended
+	MergeMin                          // the lesser number wins
+	MergeMax                          // the greater number wins
+)
+
+const _TAG_MERGE = "merge="
+
+var mergeNames = map[string]MergeStrategy{
+	"replace": MergeReplace,
+	"append":  MergeAppend,
+	"union":   MergeUnion,
+	"min":     MergeMin,
+	"max":     MergeMax,
+}
+
+func (s MergeStrategy) String() string {
+	for name, strategy := range mergeNames {
+		if strategy == s {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// WithMergeStrategy sets the merge strategy of item 'path', which is the key
+// of a global item, or 'section.key'.
+func WithMergeStrategy(path string, s MergeStrategy) Option {
+	return func(o *options) {
+		if o.mergeStrategies == nil {
+			o.mergeStrategies = make(map[string]MergeStrategy)
+		}
+		o.mergeStrategies[path] = s
+	}
+}
+
+// appendItem appends the elements of val to item 'key' of section sec, or
+// sets the item if it's absent, as 'key += val' does.
+func (conf *Conf) appendItem(sec *section, key, val string) *Item {
+	if old := sec.get(key); old != nil {
+		val = old.val + string(old.elementSep()) + val
+	}
+
+	return conf.putItem(sec, key, val)
+}
+
+// DuplicateKeys is the policy for a key repeated in a section of one file.
+type DuplicateKeys int
+
+const (
+	DuplicateLastWins  DuplicateKeys = iota // the later value wins, or is merged by its strategy
+	DuplicateFirstWins                      // the later value is dropped
+	DuplicateError                          // parsing fails with E_DUP_KEY
+	DuplicateCollect                        // the values are collected into an array
+)
+
+var duplicateNames = map[string]DuplicateKeys{
+	"last-wins":  DuplicateLastWins,
+	"first-wins": DuplicateFirstWins,
+	"error":      DuplicateError,
+	"collect":    DuplicateCollect,
+}
+
+func (d DuplicateKeys) String() string {
+	for name, policy := range duplicateNames {
+		if policy == d {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// WithDuplicateKeys sets the policy for keys repeated in a file, which is
+// DuplicateLastWins by default. Items set again by later layers, e.g.
+// included files, are still merged by their strategies.
+func WithDuplicateKeys(d DuplicateKeys) Option {
+	return func(o *options) {
+		o.duplicateKeys = d
+	}
+}
+
+// setDuplicate sets item 'key' of section sec, which is repeated in a file,
+// by the policy of duplicate keys. It returns nil if the value is dropped.
+// DuplicateError is reported by the parser, as it knows the line.
+func (conf *Conf) setDuplicate(sec *section, key, val string) *Item {
+	old := sec.get(key)
+	switch conf.opts.duplicateKeys {
+	case DuplicateFirstWins:
+		return nil
+	case DuplicateCollect:
+		val = old.val + string(old.elementSep()) + val
+	}
+
+	return conf.putItem(sec, key, val)
+}
+
+// setItem sets item 'key' of section sec, whose path is 'path'. If the item
+// exists, the value is merged by its strategy.
+func (conf *Conf) setItem(sec *section, path, key, val string) (*Item, error) {
+	if old := sec.get(key); old != nil {
+		if s := conf.opts.mergeStrategies[path]; s != MergeReplace {
+			merged, err := mergeValues(s, old, val)
+			if err != nil {
+				return nil, goutils.NewErr("failed to merge '%s' by %s: %s", path, s, err)
+			}
+			val = merged
+		}
+	}
+
+	return conf.putItem(sec, key, val), nil
+}
+
+// putItem puts a new item 'key' into section sec. It keeps the element
+// separator and the position of the item it replaces, if any.
+func (conf *Conf) putItem(sec *section, key, val string) *Item {
+	item := conf.newItem(key, val)
+	if old := sec.get(key); old != nil {
+		item.sep = old.sep
+	}
+	sec.set(key, item)
+	return item
+}
+
+func mergeValues(s MergeStrategy, oldItem *Item, val string) (string, error) {
+	old, sep := oldItem.val, string(oldItem.elementSep())
+	switch s {
+	case MergeAppend:
+		return old + sep + val, nil
+	case MergeUnion:
+		newItem := Item{val: val, sep: oldItem.sep}
+		eles := oldItem.ToStringArray()
+		seen := make(map[string]bool, len(eles))
+		for _, ele := range eles {
+			seen[ele] = true
+		}
+		for _, ele := range newItem.ToStringArray() {
+			if !seen[ele] {
+				seen[ele] = true
+				eles = append(eles, ele)
+			}
+		}
+		return strings.Join(eles, sep), nil
+	case MergeMin, MergeMax:
+		a, err := parseFloat(old)
+		if err != nil {
+			return "", err
+		}
+		b, err := parseFloat(val)
+		if err != nil {
+			return "", err
+		}
+		if (s == MergeMin) == (a <= b) {
+			return old, nil
+		}
+		return val, nil
+	}
+
+	return val, nil
+}
+
+// mergeOptions returns the merge strategies declared by the tags of the
+// fields of the config object. As the items of the fields aren't known
+// before parsing, a strategy is set for every name a field may match.
+// Paths of the fields are prefixed by 'section.', if the object is loaded
+// from a section.
+func mergeOptions(configObjPtr interface{}, section string) ([]Option, error) {
+	t := reflect.TypeOf(configObjPtr)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var opts []Option
+	prefix := ""
+	if section != "" {
+		prefix = section + string(_PATH_SEP)
+	}
+	err := walkMergeTags(t.Elem(), []string{prefix}, func(paths []string, s MergeStrategy) {
+		for _, path := range paths {
+			opts = append(opts, WithMergeStrategy(path, s))
+		}
+	})
+	return opts, err
+}
+
+func walkMergeTags(t reflect.Type, prefixes []string, fn func(paths []string, s MergeStrategy)) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		var paths []string
+		for _, prefix := range prefixes {
+			for _, name := range optNameCandidates(field.Name) {
+				paths = append(paths, prefix+name)
+			}
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			if prefixes[0] != "" {
+				continue // sections aren't nested
+			}
+			for idx := range paths {
+				paths[idx] += string(_PATH_SEP)
+			}
+			if err := walkMergeTags(field.Type, paths, fn); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, opt := range strings.Split(field.Tag.Get(_TAG), ",") {
+			opt = strings.TrimSpace(opt)
+			if !strings.HasPrefix(opt, _TAG_MERGE) {
+				continue
+			}
+			s, ok := mergeNames[opt[len(_TAG_MERGE):]]
+			if !ok {
+				return goutils.NewErr("unknown merge strategy of field %s: %s", field.Name, opt)
+			}
+			fn(paths, s)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+/**
+ * Unit test cases for merge strategies
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 04:02:17
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeStrategyTags(t *testing.T) {
+	defaults := []byte("allowed_hosts: a b\nmax_conns: 100\nname: default\n[db]\nreplicas: r1\n")
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("allowed_hosts: b c\nmax_conns: 200\nname: app\n[db]\nreplicas: r2\n"), 0644)
+
+	configObj := struct {
+		AllowedHosts []string `goconf:"merge=union"`
+		MaxConns     int      `goconf:"merge=min"`
+		Name         string
+		Db           struct {
+			Replicas []string `goconf:"merge=append"`
+		}
+	}{}
+	if err := LoadWithDefaults(&configObj, defaults, path); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+
+	if !reflect.DeepEqual(configObj.AllowedHosts, []string{"a", "b", "c"}) {
+		t.Errorf("union, output: %v", configObj.AllowedHosts)
+	}
+	if configObj.MaxConns != 100 || configObj.Name != "app" {
+		t.Errorf("min and replace, obj: %+v", configObj)
+	}
+	if !reflect.DeepEqual(configObj.Db.Replicas, []string{"r1", "r2"}) {
+		t.Errorf("append, output: %v", configObj.Db.Replicas)
+	}
+
+	bad := struct {
+		Name string `goconf:"merge=sum"`
+	}{}
+	if err := Load(&bad, path); err == nil {
+		t.Errorf("need an error for an unknown strategy")
+	}
+}
+
+func TestMergeStrategyOptions(t *testing.T) {
+	schema := NewSchema().Merge("timeout", MergeMax)
+	conf := New("", WithSchema(schema), WithMergeStrategy("s.ids", MergeUnion))
+	content := "timeout: 5\ntimeout: 3\nname: x\nname: y\n[s]\nids: 1 2\nids: 2 3\n"
+	if err := conf.parse(bufio.NewReader(bytes.NewBufferString(content))); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	if v, _ := conf.GetStringFrom(GlobalSection, "timeout"); v != "5" {
+		t.Errorf("max, val: %s", v)
+	}
+	if v, _ := conf.GetStringFrom(GlobalSection, "name"); v != "y" {
+		t.Errorf("replace, val: %s", v)
+	}
+	if v, _ := conf.GetStringFrom("s", "ids"); v != "1 2 3" {
+		t.Errorf("union, val: %s", v)
+	}
+
+	conf = New("", WithMergeStrategy("timeout", MergeMin))
+	err := conf.parse(bufio.NewReader(bytes.NewBufferString("timeout: 5\ntimeout: 3s\n")))
+	if ErrorCode(err) != E_MERGE {
+		t.Errorf("need a merge error for a non-numeric value, err: %v", err)
+	}
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	content := "port: 80\nport: 81\n[db]\nhost: a\nhost: b\nhost: c\n"
+	expected := map[DuplicateKeys][2]string{
+		DuplicateLastWins:  {"81", "c"},
+		DuplicateFirstWins: {"80", "a"},
+		DuplicateCollect:   {"80 81", "a b c"},
+	}
+	for policy, vals := range expected {
+		conf, buf := genConf(content)
+		conf.opts.duplicateKeys = policy
+		if err := conf.parse(buf); err != nil {
+			t.Fatalf("failed to parse by %s, err: %s", policy, err)
+		}
+		port, _ := conf.GetStringFrom(GlobalSection, "port")
+		host, _ := conf.GetStringFrom("db", "host")
+		if port != vals[0] || host != vals[1] {
+			t.Errorf("not expected values by %s, port: %s, host: %s", policy, port, host)
+		}
+	}
+
+	conf, buf := genConf(content)
+	conf.opts.duplicateKeys = DuplicateError
+	if err := conf.parse(buf); ErrorCode(err) != E_DUP_KEY || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("need a duplicate key error at line 2, err: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.properties")
+	os.WriteFile(path, []byte("db.host=a\ndb.host=b\n"), 0644)
+	conf = New(path, WithDuplicateKeys(DuplicateCollect))
+	if err := conf.Parse(); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetStringArray("db.host"); len(v) != 2 {
+		t.Errorf("not expected hosts: %v", v)
+	}
+}
+
+func TestRepeatedKeysByTag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("[upstream]\nserver: 10.0.0.1:80\nserver: 10.0.0.2:80\nserver: 10.0.0.3:80\nport: 80\nport: 81\n"), 0644)
+
+	configObj := struct {
+		Upstream struct {
+			Server []string `goconf:"merge=append"`
+			Port   int
+		}
+	}{}
+	var warnings []Warning
+	if err := Load(&configObj, path, CollectWarnings(&warnings)); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if err := matchStringArray(configObj.Upstream.Server, []string{"10.0.0.1:80", "10.0.0.2:80", "10.0.0.3:80"}); err != nil {
+		t.Errorf("repeated servers are collected, err: %s", err)
+	}
+	if configObj.Upstream.Port != 81 {
+		t.Errorf("other repeated keys take the last value, port: %d", configObj.Upstream.Port)
+	}
+	if len(warnings) != 1 || warnings[0].Key != "upstream.port" {
+		t.Errorf("only the overridden port is a warning: %v", warnings)
+	}
+}
+
+func TestAppendItems(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "local.conf"), []byte("search_path += /extra/dir\n[db]\nhosts += b c\n"), 0644)
+	path := filepath.Join(dir, "app.conf")
+	os.WriteFile(path, []byte("search_path: /usr/lib\nflags += -v\nexpr: a+=b\n[db]\nhosts: a\n!include-host local.conf\n"), 0644)
+
+	conf := New(path)
+	if err := conf.Parse(); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	expected := map[string]string{"search_path": "/usr/lib /extra/dir", "flags": "-v", "expr": "a+=b", "db.hosts": "a b c"}
+	for key, val := range expected {
+		if v, _ := conf.GetString(key); v != val {
+			t.Errorf("not expected '%s', val: %q", key, v)
+		}
+	}
+
+	local := filepath.Join(dir, "local.conf")
+	patch := []Change{{Op: OpSet, Key: "search_path", Value: "/opt"}}
+	if err := PatchFile(local, patch); err != nil {
+		t.Fatalf("failed to patch, err: %s", err)
+	}
+	if out, _ := os.ReadFile(local); string(out) != "search_path: /opt\n[db]\nhosts += b c\n" {
+		t.Errorf("not expected output: %q", out)
+	}
+}
@@ -0,0 +1,81 @@
+/**
+ * Messages of the errors for operators, e.g. a missing item or a bad value,
+ * are formatted from a catalog, so an application can localize or rephrase
+ * them. Each message has a stable key, and its arguments are referred to by
+ * index in the template.
+ *
+ *      e.g.
+ *          goconf.SetMessageCatalog(goconf.Catalog{
+ *              goconf.MsgKeyNotFound:  "配置项不存在: %[1]s",
+ *              goconf.MsgTypeMismatch: "'%[1]s' 的值 '%[2]s' 不是 %[3]s",
+ *          })
+ *
+ *  Messages missing in the catalog are the English defaults.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 01:25:37
+ */
+
+package goconf
+
+import (
+	"fmt"
+	"sync"
+)
+
+// A MessageKey identifies a message. Keys are stable across releases.
+type MessageKey string
+
+// Keys of messages, with the arguments of their templates.
+const (
+	MsgKeyNotFound     MessageKey = "key_not_found"     // key
+	MsgSectionNotFound MessageKey = "section_not_found" // section
+	MsgTypeMismatch    MessageKey = "type_mismatch"     // key, value, type
+	MsgFileNotFound    MessageKey = "file_not_found"    // path
+	MsgFilePermission  MessageKey = "file_permission"   // path
+	MsgFileIsDirectory MessageKey = "file_is_directory" // path
+	MsgFileUnreadable  MessageKey = "file_unreadable"   // path, error
+	MsgUnknownKeys     MessageKey = "unknown_keys"      // keys
+	MsgMissingFields   MessageKey = "missing_fields"    // fields
+)
+
+// A Catalog maps message keys to fmt templates.
+type Catalog map[MessageKey]string
+
+// DefaultCatalog holds the English messages.
+var DefaultCatalog = Catalog{
+	MsgKeyNotFound:     "non-exist item: %[1]s",
+	MsgSectionNotFound: "no section '%[1]s'",
+	MsgTypeMismatch:    "type mismatch of '%[1]s': value '%[2]s' isn't %[3]s",
+	MsgFileNotFound:    "config file not found: %[1]s",
+	MsgFilePermission:  "permission denied to config file: %[1]s",
+	MsgFileIsDirectory: "config file is a directory: %[1]s",
+	MsgFileUnreadable:  "failed to read config file '%[1]s': %[2]s",
+	MsgUnknownKeys:     "unknown config items: %[1]s",
+	MsgMissingFields:   "no config items for fields: %[1]s",
+}
+
+var (
+	catalogMu sync.RWMutex
+	catalog   Catalog
+)
+
+// SetMessageCatalog sets the catalog of the messages of errors, nil for the
+// defaults. It affects errors formatted after the call.
+func SetMessageCatalog(c Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog = c
+}
+
+// message formats message 'key' by the catalog.
+func message(key MessageKey, args ...interface{}) string {
+	catalogMu.RLock()
+	tmpl, ok := catalog[key]
+	catalogMu.RUnlock()
+	if !ok {
+		tmpl = DefaultCatalog[key]
+	}
+
+	return fmt.Sprintf(tmpl, args...)
+}
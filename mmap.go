@@ -0,0 +1,94 @@
+/**
+ * Read-only mode which maps the config file into memory, see WithMmap.
+ * Keys and values of the items are strings referring to the mapping, so
+ * a massive config is parsed without copying its content.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 21:40:17
+ */
+
+package goconf
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// A lineReader reads a config line by line, e.g. a *bufio.Reader.
+type lineReader interface {
+	ReadString(delim byte) (string, error)
+}
+
+// A stringReader reads lines of s as substrings, so no line is copied.
+type stringReader struct {
+	s   string
+	off int
+}
+
+func (r *stringReader) ReadString(delim byte) (string, error) {
+	if r.off >= len(r.s) {
+		return "", io.EOF
+	}
+
+	rest := r.s[r.off:]
+	idx := strings.IndexByte(rest, delim)
+	if idx < 0 {
+		r.off = len(r.s)
+		return rest, io.EOF
+	}
+	r.off += idx + 1
+	return rest[:idx+1], nil
+}
+
+// parseMapped maps file f of size bytes and parses it in place.
+func (conf *Conf) parseMapped(f *os.File, size int64, merge bool) error {
+	data, err := mmapFile(f, int(size))
+	if err != nil {
+		return fileErr(conf.filePath, err)
+	}
+	conf.mappings = append(conf.mappings, data)
+
+	s := unsafe.String(unsafe.SliceData(data), len(data))
+	if conf.lazy(merge) {
+		return conf.parseLazily(s, conf.filePath)
+	}
+	return conf.parseFrom(&stringReader{s: s}, conf.filePath, merge)
+}
+
+// detach copies the items out of the mappings and unmaps them, so the conf
+// can be changed and outlive them.
+func (conf *Conf) detach() {
+	if len(conf.mappings) == 0 {
+		return
+	}
+
+	sections := make(map[string]*section, len(conf.sections))
+	for name, s := range conf.sections {
+		d := newSection()
+		for _, item := range s.items() {
+			item.key = strings.Clone(item.key)
+			item.val = strings.Clone(item.val)
+			item.raw = strings.Clone(item.raw)
+			item.annotations = cloneAnnotations(item.annotations)
+			d.set(item.key, item)
+		}
+		sections[strings.Clone(name)] = d
+	}
+	conf.sections = sections
+
+	annotations := make(map[string]map[string]string, len(conf.annotations))
+	for name, a := range conf.annotations {
+		annotations[strings.Clone(name)] = cloneAnnotations(a)
+	}
+	conf.annotations = annotations
+	conf.unmap()
+}
+
+func (conf *Conf) unmap() {
+	for _, data := range conf.mappings {
+		munmap(data)
+	}
+	conf.mappings = nil
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package goconf
+
+import (
+	"io"
+	"os"
+)
+
+// Without mmap, the file is read into memory, which is still parsed
+// without copying the lines.
+func mmapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func munmap(data []byte) error {
+	return nil
+}
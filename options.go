@@ -0,0 +1,185 @@
+/**
+ * Options customize how a config file is parsed by New, or loaded by Load.
+ *
+ *      e.g.
+ *          var unknown []string
+ *          err := Load(confObj, "config.conf", CollectUnknownKeys(&unknown))
+ *          // unknown holds the items which no field consumed, e.g. a typo
+ *          // like 'prot: 8080'
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 16:02:51
+ */
+
+package goconf
+
+// An Option customizes New, Load and its variants. Options which don't
+// apply are ignored, e.g. DisallowUnknownKeys by New.
+type Option func(*options)
+
+// AutoKVSeparator makes the parser take the first ':' or '=' of a line
+// as the key/value separator, see WithKVSeparator.
+const AutoKVSeparator byte = 0
+
+type options struct {
+	kvSep           byte
+	disallowUnknown bool
+	unknownKeys     *[]string
+	requireAll      bool
+	itemArena       bool
+	mmap            bool
+	format          string
+	envPrefix       *string
+	mergeStrategies map[string]MergeStrategy // by item path
+	duplicateKeys   DuplicateKeys
+	decryptor       Decryptor
+	elementSep      byte
+	mapEntrySep     string
+	mapPairSep      string
+	secretResolvers map[string]SecretResolver // by scheme
+	profile         string
+	limits          Limits
+	lazySections    bool
+	skipEmpty       bool
+	warnings        *[]Warning
+	globalFallback  bool
+	interpolation   bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{kvSep: _KV_SEP, elementSep: defaultElementSep}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// DisallowUnknownKeys makes Load fail with an *UnknownKeysError if some
+// config items aren't consumed by any field of the config object.
+func DisallowUnknownKeys() Option {
+	return func(o *options) {
+		o.disallowUnknown = true
+	}
+}
+
+// CollectUnknownKeys stores the config items which aren't consumed by any
+// field into keys, as a warning list. Items of the global section are named
+// by their keys, and the others by 'section.key'.
+func CollectUnknownKeys(keys *[]string) Option {
+	return func(o *options) {
+		o.unknownKeys = keys
+	}
+}
+
+// RequireAllFields makes Load fail with a *MissingFieldsError if some fields
+// aren't matched by config items, except the fields tagged by
+// `goconf:"optional"`. It's what LoadStrict uses.
+func RequireAllFields() Option {
+	return func(o *options) {
+		o.requireAll = true
+	}
+}
+
+// WithItemArena allocates the items parsed in chunks from a pool, instead of
+// one by one, which reduces GC pressure for configs with millions of items.
+// Call Conf.Release to return the chunks when the conf isn't used any more.
+func WithItemArena() Option {
+	return func(o *options) {
+		o.itemArena = true
+	}
+}
+
+// WithKVSeparator sets the separator between key and value, which is ':' by
+// default. So INI-style files with 'key = value' are parsed by
+// New("app.ini", WithKVSeparator('=')). With AutoKVSeparator, both
+// 'key: value' and 'key = value' are accepted.
+func WithKVSeparator(sep byte) Option {
+	return func(o *options) {
+		o.kvSep = sep
+	}
+}
+
+// WithMmap makes Parse map a goconf file into memory read-only, instead of
+// reading it, and the keys and values of the items refer to the mapping
+// without being copied. The file must not be modified in place while it's
+// mapped, replacing it by rename is fine. Conf.Release unmaps the file, and
+// the files mapped by Reload, and ApplyPatch copies the items out of the
+// mapping before changing them.
+func WithMmap() Option {
+	return func(o *options) {
+		o.mmap = true
+	}
+}
+
+// WithLazySections makes Parse index the sections of a goconf file, and
+// parse the items of a section when it's first read, e.g. by Section or a
+// path 'section.key'. Methods reading the whole conf, like Walk or Load,
+// parse all the sections. Errors of a section are returned by the first
+// read of it, and reads change the conf, so it isn't safe to read by
+// several goroutines until all the sections are parsed, e.g. by a Walk
+// before it's shared. Files with directives, conditional sections,
+// profiles or inheriting sections, and the options changing all the items,
+// like WithEnvOverrides, parse the file eagerly.
+func WithLazySections() Option {
+	return func(o *options) {
+		o.lazySections = true
+	}
+}
+
+// WithFormat sets the format of the config file, instead of detecting it by
+// the extension. The format is the extension of a registered format, with
+// or without the leading '.', e.g. "json", "yaml", "ini" or "properties",
+// and "conf" for goconf files. An unknown format fails parsing with a
+// *ParseError of E_FORMAT.
+func WithFormat(format string) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithEnvOverrides makes Parse override the items by environment variables
+// named like 'PREFIX_SECTION_KEY', see Conf.OverrideFromEnv. The prefix is
+// "GOCONF" if it's empty.
+func WithEnvOverrides(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = &prefix
+	}
+}
+
+// WithDecryptor makes parsing decrypt values like 'ENC(AES256:...)' by d,
+// so Get* and Load return plaintexts, see Decryptor.
+func WithDecryptor(d Decryptor) Option {
+	return func(o *options) {
+		o.decryptor = d
+	}
+}
+
+// WithSecretResolver makes parsing resolve values like 'scheme://path#field'
+// by r, e.g. WithSecretResolver("vault", vaultResolver), see SecretResolver.
+func WithSecretResolver(scheme string, r SecretResolver) Option {
+	return func(o *options) {
+		if o.secretResolvers == nil {
+			o.secretResolvers = make(map[string]SecretResolver)
+		}
+		o.secretResolvers[scheme] = r
+	}
+}
+
+// WithElementSep sets the separator of elements of arrays, which is ' ' by
+// default. An item declared by '[@key@sep]' still uses its own separator.
+func WithElementSep(sep byte) Option {
+	return func(o *options) {
+		o.elementSep = sep
+	}
+}
+
+// WithGlobalFallback makes a key missing from a section fall back to the
+// global item of the key, so common settings are written once at the top of
+// the file and sections only set what differs. It applies to the getters,
+// the GetXxxFrom family and loading config objects.
+func WithGlobalFallback() Option {
+	return func(o *options) {
+		o.globalFallback = true
+	}
+}
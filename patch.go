@@ -0,0 +1,348 @@
+/**
+ * A patch is a list of changes to config items, which can be applied to a
+ * parsed Conf by ApplyPatch, or to a config file by PatchFile. Changes are
+ * idempotent, so a config management tool can apply the same patch again
+ * without checking the current state:
+ *          OpSet       sets the value of an item, adding it if needed
+ *          OpDelete    removes an item, if it exists
+ *          OpRename    renames an item, if it isn't renamed yet
+ *
+ *      e.g.
+ *          patch := []Change{
+ *              {Op: OpSet, Section: "server", Key: "port", Value: "8080"},
+ *              {Op: OpRename, Key: "timout", NewKey: "timeout"},
+ *          }
+ *          err := PatchFile("config.conf", patch)
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 16:45:10
+ */
+
+package goconf
+
+import (
+	"github.com/chosen0ne/goutils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type ChangeOp int
+
+const (
+	OpSet ChangeOp = iota
+	OpDelete
+	OpRename
+)
+
+func (op ChangeOp) String() string {
+	switch op {
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	case OpRename:
+		return "rename"
+	}
+	return "unknown"
+}
+
+// A Change is an edit of one config item.
+type Change struct {
+	Op       ChangeOp
+	Section  string // empty or GlobalSection for the global section
+	Key      string
+	Value    string // new value of OpSet
+	NewKey   string // new key of OpRename
+	OldValue string // value before the change, set by Diff, empty for an added item
+}
+
+func (c *Change) sectionName() string {
+	if c.Section == "" {
+		return _GLOBAL
+	}
+	return c.Section
+}
+
+func (c *Change) validate() error {
+	if c.Key == "" {
+		return goutils.NewErr("%s: an empty key", c.Op)
+	}
+
+	switch c.Op {
+	case OpSet:
+		if strings.TrimSpace(c.Value) == "" || strings.ContainsAny(c.Value, "\r\n") {
+			return goutils.NewErr("set '%s': value must be a non-empty line", c.Key)
+		}
+	case OpRename:
+		if c.NewKey == "" {
+			return goutils.NewErr("rename '%s': an empty new key", c.Key)
+		}
+	case OpDelete:
+	default:
+		return goutils.NewErr("unknown change op %d", int(c.Op))
+	}
+
+	return nil
+}
+
+// ApplyPatch applies the changes to the conf in order. It stops at the
+// first invalid change, and the changes before it stay applied. Use
+// PatchFile to save the same changes to the config file.
+func (conf *Conf) ApplyPatch(patch []Change) error {
+	if err := conf.loadSections(); err != nil {
+		return err
+	}
+	conf.detach()
+	for i := range patch {
+		c := &patch[i]
+		if err := c.validate(); err != nil {
+			return err
+		}
+
+		name := c.sectionName()
+		sec, ok := conf.sections[name]
+		switch c.Op {
+		case OpSet:
+			if !ok {
+				sec = newSection()
+				conf.sections[name] = sec
+			}
+			item := &Item{key: c.Key, val: strings.TrimSpace(c.Value), sep: conf.opts.elementSep}
+			if old := sec.get(c.Key); old != nil {
+				item.annotations, item.sep = old.annotations, old.sep
+			}
+			sec.set(c.Key, item)
+		case OpDelete:
+			if ok {
+				sec.del(c.Key)
+			}
+		case OpRename:
+			if !ok {
+				continue
+			}
+			item := sec.get(c.Key)
+			if item == nil {
+				continue
+			}
+			if sec.get(c.NewKey) != nil {
+				return goutils.NewErr("rename '%s': '%s' already exists", c.Key, c.NewKey)
+			}
+			item.key = c.NewKey
+			sec.rename(c.Key, c.NewKey)
+		}
+	}
+
+	return nil
+}
+
+// PatchFile applies the changes to config file 'path', keeping comments,
+// blank lines, the order of the other lines and their separators, ':' or
+// '='. New items are appended to
+// their sections, and new sections to the end of the file. The file is
+// written only if all the changes are valid.
+func PatchFile(path string, patch []Change) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fileErr(path, err)
+	}
+
+	lines := strings.Split(string(content), "\n")
+	// Don't take the final '\n' as an extra empty line
+	trailingNewline := len(lines) > 1 && lines[len(lines)-1] == ""
+	if trailingNewline {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i := range patch {
+		if lines, err = patchLines(lines, &patch[i]); err != nil {
+			return err
+		}
+	}
+
+	out := strings.Join(lines, "\n")
+	if trailingNewline || len(content) == 0 {
+		out += "\n"
+	}
+
+	return writeFileAtomic(path, []byte(out))
+}
+
+// A lineInfo tells what a line of a config file is.
+type lineInfo struct {
+	section  string // section the line belongs to
+	header   bool   // a section header
+	key      string // key of an item line, empty for other lines
+	rawKey   string // key as written, e.g. '[@ports@,]' of key 'ports'
+	keyStart int    // offset of the key in the line
+	sep      byte   // key/value separator of an item line
+	cont     bool   // a continuation line of the previous item line
+}
+
+func scanLines(lines []string) []lineInfo {
+	infos := make([]lineInfo, len(lines))
+	section := _GLOBAL
+	continued := false
+	heredoc := "" // end marker of a multi-line value
+	for idx, line := range lines {
+		trimmed := strings.Trim(line, _SPACE_CHARS)
+		info := &infos[idx]
+		if heredoc != "" {
+			info.section, info.cont = section, true
+			if trimmed == heredoc {
+				heredoc = ""
+			}
+			continue
+		}
+		if continued {
+			info.section, info.cont = section, true
+			continued = endsWithEscape(trimmed)
+			continue
+		}
+		if trimmed == "" || trimmed[0] == _COMMENT_TAG || trimmed[0] == _DIRECTIVE_TAG {
+			info.section = section
+			continue
+		}
+
+		if isSection(trimmed) {
+			section, _ = splitParent(strings.Trim(trimmed[1:len(trimmed)-1], _SPACE_CHARS))
+			info.header = true
+		} else if sep := kvSepIndex(line, AutoKVSeparator); sep >= 0 {
+			info.rawKey = strings.Trim(line[:sep], _SPACE_CHARS)
+			info.sep = line[sep]
+			if strings.HasSuffix(line[:sep+1], _APPEND_OP) {
+				// 'key += value' is set as 'key: value'
+				info.rawKey = strings.Trim(line[:sep-1], _SPACE_CHARS)
+				info.sep = _KV_SEP
+			}
+			info.key, _, _ = parseArrayKey(info.rawKey, _DEFAULT_SEP)
+			info.keyStart = strings.Index(line, info.key)
+			continued = endsWithEscape(trimmed)
+			heredoc, _ = heredocMarker(strings.Trim(line[sep+1:], _SPACE_CHARS))
+		}
+		info.section = section
+	}
+
+	return infos
+}
+
+func patchLines(lines []string, c *Change) ([]string, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	name := c.sectionName()
+	infos := scanLines(lines)
+	find := func(key string) int {
+		// the last one wins, as in parsing
+		for idx := len(infos) - 1; idx >= 0; idx-- {
+			if infos[idx].section == name && infos[idx].key == key {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	idx := find(c.Key)
+	// end is past the continuation lines of the item
+	end := idx + 1
+	for end > 0 && end < len(infos) && infos[end].cont {
+		end++
+	}
+	switch c.Op {
+	case OpSet:
+		if idx >= 0 {
+			indent := lines[idx][:strings.Index(lines[idx], infos[idx].rawKey)]
+			lines[idx] = formatItemLine(indent, infos[idx].rawKey, infos[idx].sep, c.Value)
+			return append(lines[:idx+1], lines[end:]...), nil
+		}
+		return insertItem(lines, infos, name, formatItemLine("", c.Key, _KV_SEP, c.Value)), nil
+	case OpDelete:
+		if idx < 0 {
+			return lines, nil
+		}
+		return append(lines[:idx], lines[end:]...), nil
+	case OpRename:
+		if idx < 0 {
+			return lines, nil
+		}
+		if find(c.NewKey) >= 0 {
+			return nil, goutils.NewErr("rename '%s': '%s' already exists", c.Key, c.NewKey)
+		}
+		start := infos[idx].keyStart
+		lines[idx] = lines[idx][:start] + c.NewKey + lines[idx][start+len(c.Key):]
+	}
+
+	return lines, nil
+}
+
+// formatItemLine formats 'key: value', or 'key = value' if sep is '='.
+func formatItemLine(indent, key string, sep byte, val string) string {
+	if sep == _KV_SEP_EQ {
+		return indent + key + " = " + strings.TrimSpace(val)
+	}
+	return indent + key + string(_KV_SEP) + " " + strings.TrimSpace(val)
+}
+
+// insertItem puts line after the last item of section 'name'.
+func insertItem(lines []string, infos []lineInfo, name, line string) []string {
+	pos := -1
+	for idx, info := range infos {
+		if info.section != name {
+			continue
+		}
+		if info.header || info.key != "" || info.cont {
+			pos = idx + 1
+		}
+	}
+
+	if pos < 0 {
+		if name != _GLOBAL {
+			// A new section at the end of file
+			return append(lines, "", string(_SECTION_LEFT)+name+string(_SECTION_RIGHT), line)
+		}
+		// No global items, put it before the first section
+		pos = 0
+		for pos < len(infos) && infos[pos].section == _GLOBAL {
+			pos++
+		}
+	}
+
+	lines = append(lines, "")
+	copy(lines[pos+1:], lines[pos:])
+	lines[pos] = line
+	return lines
+}
+
+// writeFileAtomic replaces file 'path' by content, so readers never see
+// a partially written config.
+func writeFileAtomic(path string, content []byte) error {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return fileErr(path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return fileErr(path, err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fileErr(path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fileErr(path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fileErr(path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+/**
+ * Unit test cases for patches
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 17:20:33
+ */
+
+package goconf
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testPatch = []Change{
+	{Op: OpSet, Key: "port", Value: "8080"},
+	{Op: OpSet, Key: "name", Value: "app"},
+	{Op: OpRename, Section: "db", Key: "hots", NewKey: "host"},
+	{Op: OpDelete, Section: "db", Key: "debug"},
+	{Op: OpSet, Section: "cache", Key: "size", Value: "10"},
+}
+
+func TestApplyPatch(t *testing.T) {
+	conf, buf := genConf("port: 80\n[db]\nhots: h1\ndebug: true\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	// twice, as changes are idempotent
+	for i := 0; i < 2; i++ {
+		if err := conf.ApplyPatch(testPatch); err != nil {
+			t.Fatalf("failed to apply patch, err: %s", err)
+		}
+	}
+
+	if v, _ := conf.GetStringFrom(GlobalSection, "port"); v != "8080" {
+		t.Errorf("port, val: %s", v)
+	}
+	if v, _ := conf.GetStringFrom("db", "host"); v != "h1" || conf.HasItem("db.hots") || conf.HasItem("db.debug") {
+		t.Errorf("db section, items: %v", conf.sections["db"])
+	}
+	if v, _ := conf.GetIntFrom("cache", "size"); v != 10 {
+		t.Errorf("cache.size, val: %d", v)
+	}
+}
+
+func TestPatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p.conf")
+	content := "# header\nport: 80\n\n[db]\n  hots: h1   # host\ndebug: true\n"
+	os.WriteFile(path, []byte(content), 0600)
+
+	for i := 0; i < 2; i++ {
+		if err := PatchFile(path, testPatch); err != nil {
+			t.Fatalf("failed to patch file, err: %s", err)
+		}
+	}
+
+	expected := "# header\nport: 8080\nname: app\n\n[db]\n  host: h1   # host\n\n[cache]\nsize: 10\n"
+	if out, _ := os.ReadFile(path); string(out) != expected {
+		t.Errorf("not expected output, output: %q, expected: %q", out, expected)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("file mode should be kept, mode: %s", info.Mode())
+	}
+
+	bad := []Change{{Op: OpSet, Key: "a", Value: ""}}
+	if err := PatchFile(path, bad); err == nil {
+		t.Errorf("need an error for an empty value")
+	}
+
+	// Errors of writing keep the os errors
+	err := writeFileAtomic(filepath.Join(filepath.Dir(path), "no_such_dir", "p.conf"), []byte(expected))
+	if !errors.Is(err, fs.ErrNotExist) || ErrorCode(err) != E_FILE_NOT_FOUND {
+		t.Errorf("need a not exist error, err: %v", err)
+	}
+}
+
+func TestPatchContinuedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p.conf")
+	content := "cmd: run \\\n  --fast\ndsn: a=1;\\\n  b=2\n[db]\nhost: h\n"
+	os.WriteFile(path, []byte(content), 0600)
+
+	patch := []Change{
+		{Op: OpSet, Key: "cmd", Value: "stop"},
+		{Op: OpDelete, Key: "dsn"},
+		{Op: OpSet, Key: "name", Value: "app"},
+	}
+	if err := PatchFile(path, patch); err != nil {
+		t.Fatalf("failed to patch file, err: %s", err)
+	}
+
+	expected := "cmd: stop\nname: app\n[db]\nhost: h\n"
+	if out, _ := os.ReadFile(path); string(out) != expected {
+		t.Errorf("not expected output, output: %q, expected: %q", out, expected)
+	}
+}
+
+func TestPatchHeredoc(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p.conf")
+	content := "query: \"\"\"\nname: x\n\"\"\"\ncert: <<EOF\nabc\nEOF\nport: 80\n"
+	os.WriteFile(path, []byte(content), 0600)
+
+	patch := []Change{{Op: OpSet, Key: "query", Value: "q"}, {Op: OpDelete, Key: "cert"}, {Op: OpSet, Key: "name", Value: "app"}}
+	if err := PatchFile(path, patch); err != nil {
+		t.Fatalf("failed to patch file, err: %s", err)
+	}
+
+	expected := "query: q\nport: 80\nname: app\n"
+	if out, _ := os.ReadFile(path); string(out) != expected {
+		t.Errorf("not expected output, output: %q, expected: %q", out, expected)
+	}
+}
+
+func TestPatchInheritingSection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p.conf")
+	os.WriteFile(path, []byte("[db]\nhost: h1\n[db_replica : db]\nhost: h2\n"), 0600)
+
+	patch := []Change{{Op: OpSet, Section: "db_replica", Key: "host", Value: "h3"}}
+	if err := PatchFile(path, patch); err != nil {
+		t.Fatalf("failed to patch file, err: %s", err)
+	}
+
+	expected := "[db]\nhost: h1\n[db_replica : db]\nhost: h3\n"
+	if out, _ := os.ReadFile(path); string(out) != expected {
+		t.Errorf("not expected output, output: %q, expected: %q", out, expected)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a, buf := genConf("port: 80\ndebug: true\n[db]\nhost: h1\nuser: app\n[cache]\nsize: 10\n")
+	if err := a.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	b, buf := genConf("port: 8080\n[db]\nhost: h1\nuser: root\n[log]\nlevel: info\n")
+	if err := b.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	changes := Diff(a, b)
+	var lines []string
+	for i := range changes {
+		lines = append(lines, changes[i].String())
+	}
+	expected := []string{"- debug: true", "port: 80 => 8080", "- cache.size: 10", "db.user: app => root",
+		"+ log.level: info"}
+	if err := matchStringArray(lines, expected); err != nil {
+		t.Errorf("not expected changes %v, err: %s", lines, err)
+	}
+
+	if err := a.ApplyPatch(changes); err != nil {
+		t.Fatalf("failed to apply patch, err: %s", err)
+	}
+	if changes := Diff(a, b); len(changes) != 0 {
+		t.Errorf("patched conf should be the same, changes: %v", changes)
+	}
+}
@@ -0,0 +1,150 @@
+/**
+ * PrintEffective prints the effective options at startup, e.g.
+ *
+ *      appName        = demo        # app.conf:1
+ *      db.password    = ********    # env GOCONF_DB_PASSWORD
+ *      db.port        = 3306        # app.conf:4
+ *
+ *  Secrets are redacted: items whose keys look like secrets (password, token,
+ *  ...), decrypted or interpolated secrets, and items annotated by
+ *  '#@secret: true'. Output is colored when the writer is a terminal.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 05:02:16
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	_REDACTED = "********"
+
+	_COLOR_KEY    = "\x1b[36m"
+	_COLOR_SECRET = "\x1b[33m"
+	_COLOR_SOURCE = "\x1b[90m"
+	_COLOR_RESET  = "\x1b[0m"
+)
+
+// secretWords are the words in keys of secret items.
+var secretWords = []string{"password", "passwd", "secret", "token", "credential", "private", "apikey", "api_key"}
+
+// PrintOptions controls PrintEffective.
+type PrintOptions struct {
+	// Title is printed as the first line if not empty.
+	Title string
+	// Redact reports whether the value of the item at path is a secret. If
+	// nil, IsSecret is used.
+	Redact func(path string, item *Item) bool
+	// Color colors output by ANSI escapes. ColorAuto colors it only when the
+	// writer is a terminal.
+	Color ColorMode
+	// NoSource omits the sources of values.
+	NoSource bool
+}
+
+// ColorMode is whether PrintEffective colors output.
+type ColorMode int
+
+const (
+	ColorAuto ColorMode = iota
+	ColorAlways
+	ColorNever
+)
+
+// IsSecret reports whether an item holds a secret: its key contains a word
+// like 'password' or 'token', it's decrypted, resolved from a secret manager
+// or interpolated from a secret, or it's annotated by '#@secret: true'.
+func IsSecret(path string, item *Item) bool {
+	if item.origin.secret || item.annotations["secret"] == "true" {
+		return true
+	}
+
+	key := strings.ToLower(item.key)
+	for _, word := range secretWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintEffective prints all items by path, with values aligned and secrets
+// redacted.
+func (conf *Conf) PrintEffective(w io.Writer, opts PrintOptions) error {
+	if err := conf.loadSections(); err != nil {
+		return err
+	}
+	redact := opts.Redact
+	if redact == nil {
+		redact = IsSecret
+	}
+	color := opts.Color == ColorAlways || opts.Color == ColorAuto && isTerminal(w)
+
+	type line struct {
+		path, val, source string
+		secret            bool
+	}
+	var lines []line
+	pathWidth, valWidth := 0, 0
+	conf.Walk(func(section string, item *Item) error {
+		l := line{path: itemPath(section, item.key), val: item.val, source: item.Source()}
+		if redact(l.path, item) {
+			l.val, l.secret = _REDACTED, true
+		}
+		pathWidth = max(pathWidth, utf8.RuneCountInString(l.path))
+		valWidth = max(valWidth, utf8.RuneCountInString(l.val))
+		lines = append(lines, l)
+		return nil
+	})
+
+	buf := bufio.NewWriter(w)
+	if opts.Title != "" {
+		buf.WriteString(opts.Title + "\n")
+	}
+	for _, l := range lines {
+		writeColored(buf, l.path, _COLOR_KEY, color)
+		buf.WriteString(pad(l.path, pathWidth) + " = ")
+		if l.secret {
+			writeColored(buf, l.val, _COLOR_SECRET, color)
+		} else {
+			buf.WriteString(l.val)
+		}
+		if !opts.NoSource && l.source != "" {
+			buf.WriteString(pad(l.val, valWidth) + "  ")
+			writeColored(buf, "# "+l.source, _COLOR_SOURCE, color)
+		}
+		buf.WriteString("\n")
+	}
+
+	return buf.Flush()
+}
+
+func writeColored(buf *bufio.Writer, s, color string, colored bool) {
+	if colored {
+		buf.WriteString(color + s + _COLOR_RESET)
+	} else {
+		buf.WriteString(s)
+	}
+}
+
+// pad returns the spaces to align s to width.
+func pad(s string, width int) string {
+	return strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,82 @@
+/**
+ * Profiles keep the configs of several environments in one file. Items of
+ * section '[profile:name]' override the base items if profile 'name' is
+ * selected. Keys of a profile are paths, so 'db.host' overrides 'host' of
+ * section 'db'.
+ *
+ *      e.g.
+ *          > port: 8080
+ *          > [db]
+ *          > host: localhost
+ *          > [profile:production]
+ *          > port: 80
+ *          > db.host: db.internal
+ *
+ *      With conf.SetProfile("production") or GOCONF_PROFILE=production,
+ *      'port' is 80 and 'db.host' is 'db.internal'.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:58:26
+ */
+
+package goconf
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	_PROFILE_PREFIX = "profile"
+	_PROFILE_ENV    = "GOCONF_PROFILE"
+)
+
+// WithProfile selects the profile applied after parsing, see SetProfile.
+func WithProfile(name string) Option {
+	return func(o *options) {
+		o.profile = name
+	}
+}
+
+// SetProfile selects the profile applied by Parse. Without a profile, the
+// one named by the env variable GOCONF_PROFILE is applied, if it's set. It
+// fails parsing if the profile isn't declared, unless it's selected by the
+// env variable for a file without profiles.
+func (conf *Conf) SetProfile(name string) {
+	conf.opts.profile = name
+}
+
+// applyProfile overrides the base items by the items of the selected
+// profile. All profile sections are dropped, so they aren't taken as
+// sections by Load or Walk.
+func (conf *Conf) applyProfile() error {
+	name := conf.opts.profile
+	if name == "" {
+		name = strings.Trim(os.Getenv(_PROFILE_ENV), _SPACE_CHARS)
+	}
+
+	prefix := _PROFILE_PREFIX + string(_SECTION_PREFIX_SEP)
+	var profile *section
+	var declared bool
+	for secName, sec := range conf.sections {
+		if !strings.HasPrefix(secName, prefix) {
+			continue
+		}
+		if secName[len(prefix):] == name {
+			profile = sec
+		}
+		declared = true
+		delete(conf.sections, secName)
+	}
+
+	if profile == nil {
+		// A profile of the env variable may be meant for other files
+		if name != "" && (declared || conf.opts.profile != "") {
+			return sectionNotFound(prefix + name)
+		}
+		return nil
+	}
+
+	conf.overrideItems(profile)
+	return nil
+}
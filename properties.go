@@ -0,0 +1,230 @@
+/**
+ * Java .properties config files. A key is separated from its value by '=',
+ * ':' or spaces, and a line ending with '\' continues on the next line.
+ * Dotted keys are mapped to sections by their first part, the same way
+ * as paths of GetItem.
+ *
+ *      e.g.
+ *          > name = app
+ *          > db.host = localhost
+ *          > db.pool.size = 10
+ *          > hosts = a.example.com \
+ *          >         b.example.com
+ *
+ *      is the same as config file:
+ *          > name: app
+ *          > hosts: a.example.com b.example.com
+ *          > [db]
+ *          > host: localhost
+ *          > pool.size: 10
+ *
+ *  Items with empty values are skipped, as warnings.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 22:05:31
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"github.com/chosen0ne/goutils"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	_PROP_COMMENT_TAG = '!'
+	_PROP_ESCAPE      = '\\'
+	_PROP_SPACE_CHARS = " \t\f"
+)
+
+func init() {
+	RegisterFormat(".properties", parseProperties)
+}
+
+func parseProperties(conf *Conf, r io.Reader, path string) error {
+	buf := bufio.NewReader(r)
+	lineNo := 0
+	var seen map[string]bool // items set by the file, to find duplicate keys
+	if conf.opts.duplicateKeys != DuplicateLastWins {
+		seen = make(map[string]bool)
+	}
+	for {
+		line, start, err := readLogicalLine(buf, &lineNo)
+		if err != nil {
+			return err
+		}
+		if line == "" && start == 0 {
+			return nil
+		}
+		if line == "" || line[0] == _COMMENT_TAG || line[0] == _PROP_COMMENT_TAG {
+			continue
+		}
+
+		rawKey, rawVal := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return parseErr(path, start, E_FORMAT_VALUE, "key '%s': %s", rawKey, err)
+		}
+		val, err := unescapeProperty(rawVal)
+		if err != nil {
+			return parseErr(path, start, E_FORMAT_VALUE, "value of '%s': %s", key, err)
+		}
+		if val = strings.Trim(val, _SPACE_CHARS); val == "" {
+			conf.warn(origin{file: path, line: start}, key, "an empty value is skipped")
+			continue
+		}
+
+		name, sec := key, conf.sections[_GLOBAL]
+		if dot := strings.IndexByte(key, _PATH_SEP); dot > 0 && dot < len(key)-1 {
+			name = key[dot+1:]
+			sec = conf.sections[key[:dot]]
+			if sec == nil {
+				sec = newSection()
+				conf.sections[key[:dot]] = sec
+				if err := conf.checkSections(path, start); err != nil {
+					return err
+				}
+			}
+		}
+		if sec.get(name) == nil && conf.opts.limits.MaxItems > 0 {
+			if err := conf.countItem(path, start); err != nil {
+				return err
+			}
+		}
+		var item *Item
+		if old := sec.get(name); old != nil && (seen[key] || (seen == nil && old.origin.file == path)) {
+			conf.warnDuplicate(origin{file: path, line: start}, key, old)
+		}
+		if seen != nil && seen[key] {
+			if conf.opts.duplicateKeys == DuplicateError {
+				return parseErr(path, start, E_DUP_KEY, "duplicate key '%s'", key)
+			}
+			if item = conf.setDuplicate(sec, name, val); item == nil {
+				continue
+			}
+		} else if item, err = conf.setItem(sec, key, name, val); err != nil {
+			return parseErr(path, start, E_MERGE, "%s", err)
+		} else if seen != nil {
+			seen[key] = true
+		}
+		if rawVal != val && item.val == val {
+			item.raw = rawVal
+		}
+		item.origin = origin{file: path, line: start}
+	}
+}
+
+// readLogicalLine reads a line joined with its continuation lines, with
+// leading spaces removed. start is the number of its first line, and 0 at
+// the end of input.
+func readLogicalLine(buf *bufio.Reader, lineNo *int) (line string, start int, err error) {
+	var sb strings.Builder
+	for {
+		physical, err := buf.ReadString(_NEWLINE)
+		if len(physical) == 0 && err == io.EOF {
+			return sb.String(), start, nil
+		} else if err != nil && err != io.EOF {
+			return "", 0, goutils.WrapErr(err)
+		}
+
+		*lineNo++
+		if start == 0 {
+			start = *lineNo
+		}
+		physical = strings.TrimRight(physical, "\r\n")
+		physical = strings.TrimLeft(physical, _PROP_SPACE_CHARS)
+
+		// A line ending with an odd number of '\' continues, but a comment
+		// line never does
+		if sb.Len() == 0 && physical != "" &&
+			(physical[0] == _COMMENT_TAG || physical[0] == _PROP_COMMENT_TAG) {
+			return physical, start, nil
+		}
+		if !endsWithEscape(physical) {
+			sb.WriteString(physical)
+			return sb.String(), start, nil
+		}
+		sb.WriteString(physical[:len(physical)-1])
+		if err == io.EOF {
+			return sb.String(), start, nil
+		}
+	}
+}
+
+func endsWithEscape(s string) bool {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == _PROP_ESCAPE; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits a line at the first unescaped '=', ':' or space.
+func splitProperty(line string) (key, val string) {
+	idx := 0
+	for ; idx < len(line); idx++ {
+		c := line[idx]
+		if c == _PROP_ESCAPE {
+			idx++
+			continue
+		}
+		if c == _KV_SEP_EQ || c == _KV_SEP || strings.IndexByte(_PROP_SPACE_CHARS, c) >= 0 {
+			break
+		}
+	}
+	if idx >= len(line) {
+		return line, ""
+	}
+
+	key = line[:idx]
+	rest := strings.TrimLeft(line[idx:], _PROP_SPACE_CHARS)
+	if rest != "" && (rest[0] == _KV_SEP_EQ || rest[0] == _KV_SEP) {
+		rest = strings.TrimLeft(rest[1:], _PROP_SPACE_CHARS)
+	}
+
+	return key, rest
+}
+
+// unescapeProperty resolves escapes like '\t', '\u00e9' and '\='.
+func unescapeProperty(s string) (string, error) {
+	if strings.IndexByte(s, _PROP_ESCAPE) < 0 {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for idx := 0; idx < len(s); idx++ {
+		if s[idx] != _PROP_ESCAPE || idx == len(s)-1 {
+			sb.WriteByte(s[idx])
+			continue
+		}
+
+		idx++
+		switch c := s[idx]; c {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if idx+5 > len(s) {
+				return "", goutils.NewErr("a bad unicode escape '\\%s'", s[idx:])
+			}
+			code, err := strconv.ParseUint(s[idx+1:idx+5], 16, 16)
+			if err != nil {
+				return "", goutils.NewErr("a bad unicode escape '\\%s'", s[idx:idx+5])
+			}
+			sb.WriteRune(rune(code))
+			idx += 4
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,282 @@
+/**
+ * A Provider is a source of config content, so parsing and loading don't
+ * depend on where the bytes come from: files, HTTP, etcd or custom sources.
+ *
+ *      e.g.
+ *          p := NewHTTPProvider("http://config-server/app.conf", 10*time.Second)
+ *          defer p.Close()
+ *
+ *          conf, err := ParseProvider(p)
+ *
+ *          changed := make(chan struct{}, 1)
+ *          p.Watch(changed)
+ *          for range changed {
+ *              conf, err = ParseProvider(p)
+ *          }
+ *
+ *  The format of the content is detected by the extension of the provider's
+ *  Path, if it has one, or given by WithFormat.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 03:02:36
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"github.com/chosen0ne/goutils"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A Provider reads config content from a source. Watch makes the provider
+// send to changed whenever the content changes, without blocking; a
+// provider which can't watch never sends.
+type Provider interface {
+	Read() ([]byte, error)
+	Watch(changed chan<- struct{})
+}
+
+// A ContextProvider is a provider whose reads respect the cancellation and
+// deadline of a context, e.g. a provider of a network source.
+type ContextProvider interface {
+	Provider
+	ReadContext(ctx context.Context) ([]byte, error)
+}
+
+// A PathProvider is a provider with a path, whose extension tells the format
+// of the content.
+type PathProvider interface {
+	Provider
+	Path() string
+}
+
+// ParseProvider parses the content of p into a new Conf. The options are
+// the ones of New.
+func ParseProvider(p Provider, opts ...Option) (*Conf, error) {
+	return ParseProviderContext(context.Background(), p, opts...)
+}
+
+// ParseProviderContext is ParseProvider, whose read of p and parsing stop
+// once ctx is done.
+func ParseProviderContext(ctx context.Context, p Provider, opts ...Option) (*Conf, error) {
+	path := ""
+	if pp, ok := p.(PathProvider); ok {
+		path = pp.Path()
+	}
+
+	content, err := readProvider(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := New(path, opts...)
+	conf.ctx = ctx
+	defer func() { conf.ctx = nil }()
+	if err := conf.parseContent(content); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+// readProvider reads p by ctx. A provider which isn't a ContextProvider is
+// read in a goroutine, which is left alone once ctx is done.
+func readProvider(ctx context.Context, p Provider) ([]byte, error) {
+	if cp, ok := p.(ContextProvider); ok {
+		return cp.ReadContext(ctx)
+	}
+
+	type result struct {
+		content []byte
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		content, err := p.Read()
+		done <- result{content, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.content, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// LoadProvider is Load with the content of p.
+func LoadProvider(configObjPtr interface{}, p Provider, opts ...Option) error {
+	mergeOpts, err := mergeOptions(configObjPtr, "")
+	if err != nil {
+		return err
+	}
+
+	conf, err := ParseProvider(p, append(mergeOpts, opts...)...)
+	if err != nil {
+		return err
+	}
+
+	return loadConf(configObjPtr, conf, opts)
+}
+
+// parseContent parses the content of the config file, which is bounded by
+// Limits.MaxFileSize as a file is.
+func (conf *Conf) parseContent(content []byte) error {
+	if max := conf.opts.limits.MaxFileSize; max > 0 && int64(len(content)) > max {
+		return limitErr(conf.filePath, 0, "file exceeds %d bytes", max)
+	}
+	if err := conf.parseFormat(bufio.NewReader(bytes.NewReader(content)), conf.filePath, false); err != nil {
+		return err
+	}
+
+	return conf.resolve()
+}
+
+// A pollingProvider watches a source by reading it every interval.
+type pollingProvider struct {
+	read     func(ctx context.Context) ([]byte, error)
+	interval time.Duration
+
+	once sync.Once
+	stop chan struct{}
+}
+
+func newPollingProvider(read func(ctx context.Context) ([]byte, error), interval time.Duration) *pollingProvider {
+	return &pollingProvider{read: read, interval: interval, stop: make(chan struct{})}
+}
+
+func (p *pollingProvider) Read() ([]byte, error) {
+	return p.read(context.Background())
+}
+
+func (p *pollingProvider) ReadContext(ctx context.Context) ([]byte, error) {
+	return p.read(ctx)
+}
+
+// Watch polls the source in a goroutine until Close. Failed reads are
+// ignored, and the content is compared with the last one read.
+func (p *pollingProvider) Watch(changed chan<- struct{}) {
+	last, _ := p.Read()
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			}
+
+			content, err := p.Read()
+			if err != nil || bytes.Equal(content, last) {
+				continue
+			}
+			last = content
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		}
+	}()
+}
+
+// Close stops watching.
+func (p *pollingProvider) Close() {
+	p.once.Do(func() { close(p.stop) })
+}
+
+// A FileProvider provides the content of a config file, polled every
+// interval by Watch.
+type FileProvider struct {
+	*pollingProvider
+	path string
+}
+
+func NewFileProvider(path string, interval time.Duration) *FileProvider {
+	read := func(ctx context.Context) ([]byte, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fileErr(path, err)
+		}
+		return content, nil
+	}
+
+	return &FileProvider{newPollingProvider(read, interval), path}
+}
+
+func (p *FileProvider) Path() string {
+	return p.path
+}
+
+// _HTTP_MAX_BODY is the default limit of the body read by an HTTPProvider.
+const _HTTP_MAX_BODY = 64 << 20
+
+// An HTTPProvider provides the body of a GET of a URL, polled every
+// interval by Watch.
+type HTTPProvider struct {
+	*pollingProvider
+	url     string
+	maxSize int64
+}
+
+// NewHTTPProvider creates a provider of rawURL, fetched by
+// http.DefaultClient. A body larger than 64MB fails with an E_LIMIT error,
+// see SetMaxSize.
+func NewHTTPProvider(rawURL string, interval time.Duration) *HTTPProvider {
+	p := &HTTPProvider{url: rawURL, maxSize: _HTTP_MAX_BODY}
+	read := func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+		if err != nil {
+			return nil, goutils.WrapErr(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, goutils.WrapErr(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, goutils.NewErr("failed to get %s, status: %s", rawURL, resp.Status)
+		}
+		maxSize := atomic.LoadInt64(&p.maxSize)
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+		if err != nil {
+			return nil, goutils.WrapErr(err)
+		} else if int64(len(content)) > maxSize {
+			return nil, limitErr(rawURL, 0, "body exceeds %d bytes", maxSize)
+		}
+		return content, nil
+	}
+	p.pollingProvider = newPollingProvider(read, interval)
+
+	return p
+}
+
+// SetMaxSize sets the limit of the body, in bytes.
+func (p *HTTPProvider) SetMaxSize(n int64) {
+	atomic.StoreInt64(&p.maxSize, n)
+}
+
+// Path returns the path of the URL, e.g. '/app.yaml'.
+func (p *HTTPProvider) Path() string {
+	u, err := url.Parse(p.url)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
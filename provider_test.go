@@ -0,0 +1,126 @@
+/**
+ * Unit test cases for Provider
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 03:20:52
+ */
+
+package goconf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("port: 8080\n"), 0644)
+
+	p := NewFileProvider(path, time.Millisecond)
+	defer p.Close()
+
+	configObj := struct{ Port int }{}
+	if err := LoadProvider(&configObj, p); err != nil || configObj.Port != 8080 {
+		t.Fatalf("failed to load, obj: %v, err: %v", configObj, err)
+	}
+
+	changed := make(chan struct{}, 1)
+	p.Watch(changed)
+	os.WriteFile(path, []byte("port: 9090\n"), 0644)
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("change isn't notified")
+	}
+
+	conf, err := ParseProvider(p)
+	if err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetInt("port"); v != 9090 {
+		t.Errorf("port after change, val: %d", v)
+	}
+}
+
+func TestHTTPProvider(t *testing.T) {
+	var status int32 = http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+		w.Write([]byte(`{"name": "app", "db": {"port": 3306}}`))
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(server.URL+"/app.json", time.Second)
+	defer p.Close()
+
+	conf, err := ParseProvider(p)
+	if err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetInt("db.port"); v != 3306 {
+		t.Errorf("db.port of JSON content, val: %d", v)
+	}
+
+	p.SetMaxSize(16)
+	if _, err := ParseProvider(p); ErrorCode(err) != E_LIMIT {
+		t.Errorf("need an E_LIMIT error of a large body, err: %v", err)
+	}
+	p.SetMaxSize(1 << 10)
+	if _, err := ParseProvider(p, WithLimits(Limits{MaxFileSize: 16})); ErrorCode(err) != E_LIMIT {
+		t.Errorf("need an E_LIMIT error of large content, err: %v", err)
+	}
+
+	atomic.StoreInt32(&status, http.StatusNotFound)
+	if _, err := ParseProvider(p); err == nil {
+		t.Errorf("need an error for a failed request")
+	}
+}
+
+// slowProvider is a provider which isn't a ContextProvider, whose reads
+// block until release is closed.
+type slowProvider struct{ release chan struct{} }
+
+func (p *slowProvider) Read() ([]byte, error) {
+	<-p.release
+	return []byte("port: 8080\n"), nil
+}
+
+func (p *slowProvider) Watch(changed chan<- struct{}) {}
+
+func TestParseProviderContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	p := NewHTTPProvider(server.URL+"/app.json", time.Second)
+	defer p.Close()
+	for _, p := range []Provider{p, &slowProvider{release}} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := ParseProviderContext(ctx, p)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("need a deadline error for %T, err: %v", p, err)
+		}
+	}
+
+	conf, err := ParseProviderContext(context.Background(), NewFileProvider("conf_sample.conf", time.Second))
+	if err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetIntFrom("Section1", "A"); v != 12 {
+		t.Errorf("Section1.A, val: %d", v)
+	}
+}
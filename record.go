@@ -0,0 +1,182 @@
+/**
+ * Recording of config access, for canary diffing. While recording, every
+ * item read by the getters or by loading is recorded with the value
+ * returned. The recording can be saved, and diffed against a proposed
+ * config later, which tells whether the change alters any value the
+ * application actually reads.
+ *
+ *      e.g.
+ *          rec := conf.Record()
+ *          // run the application
+ *          rec.WriteJSON(f)
+ *
+ *          // later, e.g. in the CI of config changes
+ *          rec, err := ReadRecording(f)
+ *          diffs, err := rec.DiffFile("proposed.conf")
+ *          for _, d := range diffs {
+ *              fmt.Println(d)      // e.g. "db.port: '3306' => '3307'"
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 02:20:45
+ */
+
+package goconf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/chosen0ne/goutils"
+	"io"
+	"sync"
+)
+
+// An Access is a read of an item. Items not found are recorded as well, as
+// adding them changes what the application reads. The values of secrets,
+// see IsSecret, are recorded as digests, e.g. 'sha256:9f86d081884c7d65'.
+type Access struct {
+	Section string `json:"section"` // GlobalSection for global items
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Found   bool   `json:"found"`
+}
+
+func (a *Access) path() string {
+	return itemPath(a.Section, a.Key)
+}
+
+func (a *Access) valueString() string {
+	if !a.Found {
+		return "<absent>"
+	}
+	return "'" + a.Value + "'"
+}
+
+// A Recording is the accesses of items, in the order of their first reads.
+// It's safe for concurrent use.
+type Recording struct {
+	mu       sync.Mutex
+	accesses []Access
+	seen     map[string]bool // paths recorded
+}
+
+// Record starts recording the accesses of items, and returns the recording.
+// Call it before the conf is shared by goroutines.
+func (conf *Conf) Record() *Recording {
+	conf.recording = &Recording{seen: make(map[string]bool)}
+	return conf.recording
+}
+
+// record records the read of item 'key' of section 'name', item is nil if
+// it's not found.
+func (conf *Conf) record(name, key string, item *Item) {
+	a := Access{Section: name, Key: key}
+	if item != nil {
+		a.Value, a.Found = accessValue(a.path(), item), true
+	}
+	conf.recording.add(a)
+}
+
+// accessValue returns the value of item to record. A secret is kept as a
+// digest, so a saved recording doesn't leak it but still tells a change.
+func accessValue(path string, item *Item) string {
+	if !IsSecret(path, item) {
+		return item.val
+	}
+	sum := sha256.Sum256([]byte(item.val))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// sectionName returns the name of section s, which may be a section of
+// the defaults.
+func (conf *Conf) sectionName(s *section) string {
+	for _, sections := range []map[string]*section{conf.sections, conf.defaults} {
+		for name, sec := range sections {
+			if sec == s {
+				return name
+			}
+		}
+	}
+	return _GLOBAL
+}
+
+func (rec *Recording) add(a Access) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	if path := a.path(); !rec.seen[path] {
+		rec.seen[path] = true
+		rec.accesses = append(rec.accesses, a)
+	}
+}
+
+// Accesses returns a copy of the accesses recorded.
+func (rec *Recording) Accesses() []Access {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Access(nil), rec.accesses...)
+}
+
+// WriteJSON saves the recording as a JSON array of accesses.
+func (rec *Recording) WriteJSON(w io.Writer) error {
+	if err := json.NewEncoder(w).Encode(rec.Accesses()); err != nil {
+		return goutils.WrapErr(err)
+	}
+	return nil
+}
+
+// ReadRecording reads a recording saved by WriteJSON.
+func ReadRecording(r io.Reader) (*Recording, error) {
+	var accesses []Access
+	if err := json.NewDecoder(r).Decode(&accesses); err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+
+	rec := &Recording{seen: make(map[string]bool)}
+	for _, a := range accesses {
+		rec.add(a)
+	}
+	return rec, nil
+}
+
+// An AccessDiff is an access whose value differs in another conf.
+type AccessDiff struct {
+	Access
+	NewValue string
+	NewFound bool
+}
+
+func (d *AccessDiff) String() string {
+	n := Access{Value: d.NewValue, Found: d.NewFound}
+	return fmt.Sprintf("%s: %s => %s", d.path(), d.valueString(), n.valueString())
+}
+
+// Diff replays the accesses on conf, and returns the ones which read other
+// values, or find or miss other items.
+func (rec *Recording) Diff(conf *Conf) []AccessDiff {
+	var diffs []AccessDiff
+	for _, a := range rec.Accesses() {
+		d := AccessDiff{Access: a}
+		conf.loadSection(a.Section)
+		if item, ok := conf.lookup(conf.sections[a.Section], a.Key); ok {
+			d.NewValue, d.NewFound = accessValue(a.path(), item), true
+		}
+		if d.NewFound != a.Found || d.NewValue != a.Value {
+			diffs = append(diffs, d)
+		}
+	}
+
+	return diffs
+}
+
+// DiffFile is Diff with the conf parsed from file 'path'.
+func (rec *Recording) DiffFile(path string, opts ...Option) ([]AccessDiff, error) {
+	conf := New(path, opts...)
+	if err := conf.Parse(); err != nil {
+		return nil, err
+	}
+
+	return rec.Diff(conf), nil
+}
@@ -0,0 +1,113 @@
+/**
+ * Unit test cases for Recording
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 02:41:19
+ */
+
+package goconf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndDiff(t *testing.T) {
+	conf, buf := genConf("name: app\nunused: 1\n[db]\nport: 3306\nhost: h1\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	rec := conf.Record()
+	conf.GetString("name")
+	conf.GetInt("db.port")
+	conf.GetStringFrom("db", "host")
+	conf.GetString("timeout")
+	conf.GetString("name")
+	configObj := struct{ Name string }{}
+	if err := loadConf(&configObj, conf, nil); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+
+	expected := []Access{
+		{GlobalSection, "name", "app", true},
+		{GlobalSection, "db.port", "3306", true},
+		{"db", "host", "h1", true},
+		{GlobalSection, "timeout", "", false},
+	}
+	accesses := rec.Accesses()
+	if len(accesses) != len(expected) {
+		t.Fatalf("not expected accesses: %v", accesses)
+	}
+	for idx, a := range accesses {
+		if a != expected[idx] {
+			t.Errorf("access %d, output: %v, expected: %v", idx, a, expected[idx])
+		}
+	}
+
+	// Save and replay against a proposed config
+	saved := bytes.Buffer{}
+	if err := rec.WriteJSON(&saved); err != nil {
+		t.Fatalf("failed to save, err: %s", err)
+	}
+	replayed, err := ReadRecording(&saved)
+	if err != nil {
+		t.Fatalf("failed to read recording, err: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "proposed.conf")
+	os.WriteFile(path, []byte("name: app\nunused: 2\ntimeout: 3s\n[db]\nport: 3307\nhost: h1\n"), 0644)
+	diffs, err := replayed.DiffFile(path)
+	if err != nil {
+		t.Fatalf("failed to diff, err: %s", err)
+	}
+
+	output := make([]string, len(diffs))
+	for idx := range diffs {
+		output[idx] = diffs[idx].String()
+	}
+	if err := matchStringArray(output, []string{
+		"db.port: '3306' => '3307'",
+		"timeout: <absent> => '3s'",
+	}); err != nil {
+		t.Errorf("not expected diffs, err: %s, output: %v", err, output)
+	}
+}
+
+func TestRecordSecret(t *testing.T) {
+	conf, buf := genConf("db_password: s3cret\n#@secret: true\ndsn: mysql://app@h1\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	rec := conf.Record()
+	conf.GetString("db_password")
+	conf.GetString("dsn")
+	saved := bytes.Buffer{}
+	if err := rec.WriteJSON(&saved); err != nil {
+		t.Fatalf("failed to save, err: %s", err)
+	}
+	if strings.Contains(saved.String(), "s3cret") || strings.Contains(saved.String(), "mysql") {
+		t.Errorf("secrets are saved, output: %s", saved.String())
+	}
+	for _, a := range rec.Accesses() {
+		if !strings.HasPrefix(a.Value, "sha256:") {
+			t.Errorf("not a digest of %s, val: %s", a.path(), a.Value)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "proposed.conf")
+	os.WriteFile(path, []byte("db_password: s3cret\n#@secret: true\ndsn: mysql://app@h2\n"), 0644)
+	diffs, err := rec.DiffFile(path)
+	if err != nil {
+		t.Fatalf("failed to diff, err: %s", err)
+	}
+	if len(diffs) != 1 || diffs[0].Key != "dsn" || strings.Contains(diffs[0].String(), "mysql") {
+		t.Errorf("not expected diffs: %v", diffs)
+	}
+}
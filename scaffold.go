@@ -0,0 +1,192 @@
+/**
+ * Scaffold writes a sample config file of a config struct, so example
+ * configs are generated from the source instead of kept in sync by hand.
+ * Keys are named like 'a_example_field', values are the `default` tags or
+ * the values of the fields, and comments are the `desc` tags.
+ *
+ *      e.g.
+ *          type Config struct {
+ *              Port    int      `default:"8080" desc:"port to listen on"`
+ *              Hosts   []string `desc:"backend hosts"`
+ *              DB      struct {
+ *                  User string `default:"app"`
+ *              }
+ *          }
+ *
+ *          Scaffold(&Config{}, os.Stdout) writes:
+ *          > # port to listen on
+ *          > port: 8080
+ *          > # backend hosts
+ *          > # hosts:
+ *          >
+ *          > [db]
+ *          > user: app
+ *
+ *      Items without a value are commented out, as empty values aren't
+ *      allowed.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 11:52:03
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+)
+
+const (
+	_TAG_DEFAULT = "default"
+	_TAG_DESC    = "desc"
+)
+
+// A fieldDoc describes the item of a field, see Scaffold.
+type fieldDoc struct {
+	section  string // "" for global items
+	key      string
+	typ      string // type of ItemSchema.Type, "" for any
+	array    bool
+	def      string // "" if there's no default
+	sep      byte   // element separator of def, 0 for the default one
+	desc     string
+	optional bool
+}
+
+// Scaffold writes a sample config file of the config object to w. Global
+// items are written first, then the sections of struct fields, and the
+// ones of maps of structs as commented examples.
+func Scaffold(configObjPtr interface{}, w io.Writer) error {
+	docs, err := fieldDocs(configObjPtr)
+	if err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(w)
+	section := ""
+	for _, doc := range docs {
+		example := strings.HasSuffix(doc.section, string(_SECTION_PREFIX_SEP)+"name")
+		if doc.section != section {
+			section = doc.section
+			if example {
+				buf.WriteString("\n# [" + section + "]\n")
+			} else {
+				buf.WriteString("\n[" + section + "]\n")
+			}
+		}
+
+		if doc.desc != "" {
+			buf.WriteString("# " + doc.desc + "\n")
+		}
+		if doc.def == "" || example {
+			buf.WriteString("# ")
+		}
+		key := doc.key
+		if doc.sep != 0 {
+			key = _ARRAY_LEFT + key + string(_ARRAY_SEP_TAG) + string(doc.sep) + string(_SECTION_RIGHT)
+		}
+		buf.WriteString(strings.TrimRight(key+": "+doc.def, _SPACE_CHARS) + "\n")
+	}
+
+	return buf.Flush()
+}
+
+// fieldDocs describes the items of the fields of a config object, as Load
+// maps them. Sections of maps of structs are named 'prefix:name'.
+func fieldDocs(configObjPtr interface{}) ([]fieldDoc, error) {
+	v := reflect.ValueOf(configObjPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("configObj must be a pointer to a struct")
+	}
+
+	conf := New("")
+	var items, sections []fieldDoc
+	t := v.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Elem().Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		var section string
+		var sv reflect.Value
+		if isSectionMap(field.Type) {
+			section = sectionPrefix(&field) + string(_SECTION_PREFIX_SEP) + "name"
+			ele := field.Type.Elem()
+			if ele.Kind() == reflect.Ptr {
+				ele = ele.Elem()
+			}
+			sv = reflect.New(ele).Elem()
+		} else if isSectionStruct(field.Type) {
+			section, sv = keyName(field.Name), fv
+		} else {
+			doc, err := conf.fieldDoc(&field, fv)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, doc)
+			continue
+		}
+
+		for j := 0; j < sv.NumField(); j++ {
+			inner := sv.Type().Field(j)
+			if inner.PkgPath != "" {
+				continue
+			}
+			doc, err := conf.fieldDoc(&inner, sv.Field(j))
+			if err != nil {
+				return nil, err
+			}
+			doc.section = section
+			sections = append(sections, doc)
+		}
+	}
+
+	return append(items, sections...), nil
+}
+
+// fieldDoc describes the item of a field, whose value is fv.
+func (conf *Conf) fieldDoc(field *reflect.StructField, fv reflect.Value) (fieldDoc, error) {
+	doc := fieldDoc{
+		key:      keyName(field.Name),
+		desc:     field.Tag.Get(_TAG_DESC),
+		optional: hasTagOpt(field, _TAG_OPTIONAL),
+	}
+
+	typ := field.Type
+	if typ.Kind() == reflect.Slice && converterOf(typ) == nil && typ.Elem().Kind() != reflect.Uint8 {
+		doc.array, typ = true, typ.Elem()
+	}
+	doc.typ = fieldType(typ)
+
+	if def, ok := field.Tag.Lookup(_TAG_DEFAULT); ok {
+		doc.def = def
+		return doc, nil
+	}
+	if fv.IsZero() || isSectionStruct(fv.Type()) {
+		return doc, nil
+	}
+
+	val, err := conf.fieldValue(fv)
+	if err != nil {
+		return doc, errors.New("field " + field.Name + ": " + err.Error())
+	}
+	switch val := val.(type) {
+	case string:
+		doc.def = val
+	case []interface{}:
+		eles := make([]string, len(val))
+		for idx, ele := range val {
+			eles[idx] = ele.(string)
+		}
+		doc.def = strings.Join(eles, string(conf.opts.elementSep))
+	case matrixRows:
+		doc.def, doc.sep = strings.Join(val, string(_MATRIX_ROW_SEP)+" "), _MATRIX_ROW_SEP
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,99 @@
+/**
+ * Streaming reader of config lines. The input is scanned in blocks of whole
+ * lines by a bufio.Scanner, whose buffer is reused, and a block is copied
+ * into a string once. Lines are substrings of their blocks, so parsing
+ * doesn't allocate a string per line.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 13:31:08
+ */
+
+package goconf
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strings"
+)
+
+const _BLOCK_SIZE = 64 * 1024
+
+// A blockReader reads the lines of r by blocks. The delimiter of lines is
+// always '\n'.
+type blockReader struct {
+	scanner *bufio.Scanner
+	block   stringReader
+}
+
+// newBlockReader returns a blockReader of r. A line longer than maxLine
+// fails with bufio.ErrTooLong, or lines aren't limited if maxLine is 0.
+func newBlockReader(r io.Reader, maxLine int) *blockReader {
+	if maxLine <= 0 {
+		maxLine = math.MaxInt
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, _BLOCK_SIZE), maxLine)
+	scanner.Split(scanBlocks)
+	return &blockReader{scanner: scanner}
+}
+
+func (r *blockReader) ReadString(delim byte) (string, error) {
+	if r.block.off >= len(r.block.s) {
+		if !r.scanner.Scan() {
+			if err := r.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		r.block = stringReader{s: r.scanner.Text()}
+	}
+
+	return r.block.ReadString(delim)
+}
+
+// A crReader reads lines ended by a lone '\r', e.g. of a config edited on
+// old Macs, as separate lines, and drops the UTF-8 BOM of the first line.
+// Lines ended by "\r\n" are returned as they are, as '\r' is trimmed with
+// spaces.
+type crReader struct {
+	r       lineReader
+	rest    string // lines after a lone '\r', not returned yet
+	err     error  // error of reading rest
+	started bool
+}
+
+func (cr *crReader) ReadString(delim byte) (string, error) {
+	if cr.rest == "" {
+		line, err := cr.r.ReadString(delim)
+		if !cr.started {
+			cr.started = true
+			line = strings.TrimPrefix(line, _BOM)
+		}
+		cr.rest, cr.err = line, err
+	}
+
+	line := cr.rest
+	if idx := strings.IndexByte(line, _CR); idx >= 0 && idx < len(line)-1 && line[idx+1] != delim {
+		cr.rest = line[idx+1:]
+		return line[:idx+1], nil
+	}
+	cr.rest = ""
+	return line, cr.err
+}
+
+// scanBlocks is a bufio.SplitFunc which returns the whole lines in data,
+// or the rest of the input at EOF.
+func scanBlocks(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if idx := bytes.LastIndexByte(data, _NEWLINE); idx >= 0 {
+		return idx + 1, data[:idx+1], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+
+	// Request more data
+	return 0, nil, nil
+}
@@ -0,0 +1,563 @@
+/**
+ * Schema declares what a config file is expected to contain, so a parsed
+ * Conf can be checked once by 'Validate' instead of by hand after every Load.
+ *
+ *      e.g.
+ *          schema := NewSchema()
+ *          schema.Section("database").Required().ItemCount(2, 0)
+ *          schema.Item("database.port").Type("uint").Required()
+ *          schema.Item("database.hosts").Type("string").Array(1, 3)
+ *          schema.Constrain(
+ *              LessThan("min_conns", "max_conns"),
+ *              SumAtMost(1.0, "read_ratio", "write_ratio"),
+ *          )
+ *          if errs := conf.Validate(schema); len(errs) != 0 {
+ *              // report errs
+ *          }
+ *
+ *      A schema can also be derived from a config struct by SchemaOf.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 10:21:37
+ */
+
+package goconf
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chosen0ne/goutils"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// A Schema is a set of rules which a Conf is validated against.
+type Schema struct {
+	sections    []*SectionSchema // in order of declaration
+	items       []*ItemSchema    // in order of declaration
+	constraints []Constraint
+	merges      map[string]MergeStrategy
+}
+
+// A SectionSchema declares whether a section must be present, and how
+// many items it may hold.
+type SectionSchema struct {
+	name     string
+	aliases  []string // other names of the section, see SchemaOf
+	required bool
+	minItems int
+	maxItems int // 0 means no limit
+}
+
+// An ItemSchema declares the type of an item, whether it must be present,
+// and the number of elements of an array.
+type ItemSchema struct {
+	path     string   // a global key or 'section.key'
+	aliases  []string // other paths of the item, see SchemaOf
+	typ      string   // "" means any type
+	required bool
+	array    bool
+	minLen   int
+	maxLen   int // 0 means no limit
+}
+
+// A Constraint is an invariant between config items. Check returns
+// nil if the invariant holds.
+type Constraint interface {
+	Check(conf *Conf) error
+}
+
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Section returns the schema of section 'name', declaring it if needed.
+// A newly declared section is optional and may hold any number of items.
+func (schema *Schema) Section(name string) *SectionSchema {
+	for _, ss := range schema.sections {
+		if ss.name == name {
+			return ss
+		}
+	}
+
+	ss := &SectionSchema{name: name}
+	schema.sections = append(schema.sections, ss)
+	return ss
+}
+
+// Required makes a missing section a violation.
+func (ss *SectionSchema) Required() *SectionSchema {
+	ss.required = true
+	return ss
+}
+
+// Optional allows the section to be absent. The item counts are only
+// checked when it's present.
+func (ss *SectionSchema) Optional() *SectionSchema {
+	ss.required = false
+	return ss
+}
+
+// ItemCount bounds the number of items of the section. A max of 0
+// means no upper bound.
+func (ss *SectionSchema) ItemCount(min, max int) *SectionSchema {
+	ss.minItems = min
+	ss.maxItems = max
+	return ss
+}
+
+// find returns the section of the schema by its name or aliases.
+func (ss *SectionSchema) find(conf *Conf) (*section, bool) {
+	for _, name := range append([]string{ss.name}, ss.aliases...) {
+		if section, ok := conf.sections[name]; ok {
+			return section, true
+		}
+	}
+	return nil, false
+}
+
+func (ss *SectionSchema) check(conf *Conf) error {
+	section, ok := ss.find(conf)
+	if !ok {
+		if ss.required {
+			return goutils.NewErr("missing required section '%s'", ss.name)
+		}
+		return nil
+	}
+
+	if section.len() < ss.minItems {
+		return goutils.NewErr("section '%s' needs at least %d items, got %d",
+			ss.name, ss.minItems, section.len())
+	}
+	if ss.maxItems > 0 && section.len() > ss.maxItems {
+		return goutils.NewErr("section '%s' allows at most %d items, got %d",
+			ss.name, ss.maxItems, section.len())
+	}
+
+	return nil
+}
+
+// Item returns the schema of item 'path', a global key or 'section.key',
+// declaring it if needed. A newly declared item is optional and may be of
+// any type.
+func (schema *Schema) Item(path string) *ItemSchema {
+	for _, is := range schema.items {
+		if is.path == path {
+			return is
+		}
+	}
+
+	is := &ItemSchema{path: path}
+	schema.items = append(schema.items, is)
+	return is
+}
+
+// Type declares the type of the item, or of its elements if it's an array:
+// "int", "uint", "float", "bool", "string", "duration", "percent",
+// "bytes", "ip", "cidr", "url" or "regexp".
+func (is *ItemSchema) Type(typ string) *ItemSchema {
+	is.typ = typ
+	return is
+}
+
+// Required makes a missing item a violation.
+func (is *ItemSchema) Required() *ItemSchema {
+	is.required = true
+	return is
+}
+
+// Optional allows the item to be absent.
+func (is *ItemSchema) Optional() *ItemSchema {
+	is.required = false
+	return is
+}
+
+// Array declares the item as an array, and bounds the number of its
+// elements. A max of 0 means no upper bound.
+func (is *ItemSchema) Array(minLen, maxLen int) *ItemSchema {
+	is.array = true
+	is.minLen = minLen
+	is.maxLen = maxLen
+	return is
+}
+
+// typeCheckers check values of the types of ItemSchema.Type.
+var typeCheckers = map[string]func(item *Item) error{
+	"int":      func(item *Item) error { _, err := item.ToInt(); return err },
+	"uint":     func(item *Item) error { _, err := item.ToUint(); return err },
+	"float":    func(item *Item) error { _, err := item.ToFloat(); return err },
+	"bool":     func(item *Item) error { _, err := item.ToBool(); return err },
+	"string":   func(item *Item) error { return nil },
+	"duration": func(item *Item) error { _, err := item.ToDuration(); return err },
+	"percent":  func(item *Item) error { _, err := item.ToPercent(); return err },
+	"bytes":    func(item *Item) error { _, err := item.ToBytes(); return err },
+	"ip":       func(item *Item) error { _, err := item.ToIP(); return err },
+	"cidr":     func(item *Item) error { _, err := item.ToCIDR(); return err },
+	"url":      func(item *Item) error { _, err := item.ToURL(); return err },
+	"regexp":   func(item *Item) error { _, err := item.ToRegexp(); return err },
+}
+
+func (is *ItemSchema) check(conf *Conf) error {
+	global := conf.sections[_GLOBAL]
+	var item *Item
+	var ok bool
+	for _, path := range append([]string{is.path}, is.aliases...) {
+		if item, ok = conf.lookup(global, path); ok {
+			break
+		}
+	}
+	if !ok {
+		if is.required {
+			return goutils.NewErr("missing required item '%s'", is.path)
+		}
+		return nil
+	}
+
+	err := is.checkItem(item)
+	if err != nil && item.origin.file != "" {
+		// located for operators, e.g. 'app.conf:12: ...'
+		return fmt.Errorf("%s: %w", item.Source(), err)
+	}
+	return err
+}
+
+func (is *ItemSchema) checkItem(item *Item) error {
+	checker := typeCheckers[is.typ]
+	if checker == nil && is.typ != "" {
+		return goutils.NewErr("unknown type '%s' of item '%s'", is.typ, is.path)
+	}
+	if !is.array {
+		if checker != nil {
+			return checker(item)
+		}
+		return nil
+	}
+
+	eles := item.ToStringArray()
+	if len(eles) < is.minLen {
+		return goutils.NewErr("'%s' needs at least %d elements, got %d", is.path, is.minLen, len(eles))
+	}
+	if is.maxLen > 0 && len(eles) > is.maxLen {
+		return goutils.NewErr("'%s' allows at most %d elements, got %d", is.path, is.maxLen, len(eles))
+	}
+	if checker == nil {
+		return nil
+	}
+	for _, ele := range eles {
+		if err := checker(&Item{key: item.key, val: ele}); err != nil {
+			var typeErr *TypeError
+			if errors.As(err, &typeErr) {
+				err = typeErr.Err
+			}
+			// reported as an error of the array
+			return &TypeError{Key: item.key, Val: item.val, Type: is.typ + " array", Err: err}
+		}
+	}
+
+	return nil
+}
+
+// Constrain adds inter-key constraints to the schema.
+func (schema *Schema) Constrain(constraints ...Constraint) *Schema {
+	schema.constraints = append(schema.constraints, constraints...)
+	return schema
+}
+
+// Merge declares the merge strategy of item 'path', a global key or
+// 'section.key'. It's used by parsing with WithSchema.
+func (schema *Schema) Merge(path string, s MergeStrategy) *Schema {
+	if schema.merges == nil {
+		schema.merges = make(map[string]MergeStrategy)
+	}
+	schema.merges[path] = s
+	return schema
+}
+
+// WithSchema makes parsing merge items by the strategies declared in schema.
+func WithSchema(schema *Schema) Option {
+	return func(o *options) {
+		for path, s := range schema.merges {
+			WithMergeStrategy(path, s)(o)
+		}
+	}
+}
+
+// Validate checks the conf against all the rules of schema, and returns
+// every violation found. A nil slice means the conf is valid. The items of
+// a missing required section aren't checked, as the section is reported.
+func (conf *Conf) Validate(schema *Schema) []error {
+	if err := conf.loadSections(); err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	missing := make(map[string]bool) // names of missing required sections
+	for _, ss := range schema.sections {
+		if err := ss.check(conf); err != nil {
+			errs = append(errs, err)
+			if _, ok := ss.find(conf); !ok {
+				for _, name := range append([]string{ss.name}, ss.aliases...) {
+					missing[name] = true
+				}
+			}
+		}
+	}
+
+	for _, is := range schema.items {
+		if dot := strings.IndexByte(is.path, _PATH_SEP); dot > 0 && missing[is.path[:dot]] {
+			continue
+		}
+		if err := is.check(conf); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, c := range schema.constraints {
+		if err := c.Check(conf); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// schemaJSON is the JSON form of a schema, see ParseSchema.
+type schemaJSON struct {
+	Sections map[string]struct {
+		Required bool `json:"required"`
+		MinItems int  `json:"min_items"`
+		MaxItems int  `json:"max_items"`
+	} `json:"sections"`
+	Items map[string]struct {
+		Type     string `json:"type"`
+		Required bool   `json:"required"`
+		Array    bool   `json:"array"`
+		MinLen   int    `json:"min_len"`
+		MaxLen   int    `json:"max_len"`
+	} `json:"items"`
+}
+
+// ParseSchema parses a schema of JSON, so a config file can be validated
+// without Go code, e.g. by the goconf command.
+//
+//	{
+//	    "sections": {"db": {"required": true, "min_items": 1}},
+//	    "items": {
+//	        "db.port": {"type": "uint", "required": true},
+//	        "db.hosts": {"type": "string", "array": true, "max_len": 3}
+//	    }
+//	}
+//
+// Sections and items are declared in order of name.
+func ParseSchema(data []byte) (*Schema, error) {
+	var sj schemaJSON
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return nil, goutils.WrapErr(err)
+	}
+
+	schema := NewSchema()
+	for _, name := range sortedKeys(sj.Sections) {
+		s := sj.Sections[name]
+		ss := schema.Section(name).ItemCount(s.MinItems, s.MaxItems)
+		ss.required = s.Required
+	}
+	for _, path := range sortedKeys(sj.Items) {
+		i := sj.Items[path]
+		if _, ok := typeCheckers[i.Type]; !ok && i.Type != "" {
+			return nil, goutils.NewErr("unknown type '%s' of item '%s'", i.Type, path)
+		}
+		is := schema.Item(path).Type(i.Type)
+		is.required = i.Required
+		if i.Array {
+			is.Array(i.MinLen, i.MaxLen)
+		}
+	}
+
+	return schema, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SchemaOf derives a schema from the fields of a config object, as Load
+// maps them. Fields are required items unless tagged by
+// `goconf:"optional"`, struct fields are required sections, and the types
+// of items are the kinds of the fields, e.g. "uint" for uint16 and an
+// array of "int" for []int. Fields of other types may hold any value.
+func SchemaOf(configObjPtr interface{}) (*Schema, error) {
+	t := reflect.TypeOf(configObjPtr)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("configObj must be a pointer to a struct")
+	}
+
+	schema := NewSchema()
+	schema.addFields(t.Elem(), nil)
+	return schema, nil
+}
+
+// addFields declares the fields of struct t, which are items of a section
+// named by one of sectionNames, or global items if it's nil.
+func (schema *Schema) addFields(t reflect.Type, sectionNames []string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || isSectionMap(field.Type) {
+			continue
+		}
+
+		names := optNameCandidates(field.Name)
+		required := !hasTagOpt(&field, _TAG_OPTIONAL)
+		if isSectionStruct(field.Type) && sectionNames == nil {
+			ss := schema.Section(names[0])
+			ss.aliases, ss.required = names[1:], required
+			schema.addFields(field.Type, names)
+			continue
+		}
+
+		var paths []string
+		for _, secName := range sectionNames {
+			for _, name := range names {
+				paths = append(paths, secName+string(_PATH_SEP)+name)
+			}
+		}
+		if sectionNames == nil {
+			paths = names
+		}
+
+		is := schema.Item(paths[0])
+		is.aliases, is.required = paths[1:], required
+		typ := field.Type
+		if typ.Kind() == reflect.Slice && converterOf(typ) == nil && typ.Elem().Kind() != reflect.Uint8 {
+			is.Array(0, 0)
+			typ = typ.Elem()
+		}
+		is.Type(fieldType(typ))
+	}
+}
+
+// fieldType returns the type of ItemSchema.Type of a field type, or "" if
+// its values can't be checked.
+func fieldType(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "duration"
+	case reflect.TypeOf(net.IP{}):
+		return "ip"
+	case reflect.TypeOf(net.IPNet{}), reflect.TypeOf(&net.IPNet{}):
+		return "cidr"
+	case reflect.TypeOf(&url.URL{}):
+		return "url"
+	case reflect.TypeOf(&regexp.Regexp{}):
+		return "regexp"
+	}
+	if converterOf(t) != nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return ""
+	}
+
+	switch kind := t.Kind(); {
+	case isUint(kind):
+		return "uint"
+	case isInt(kind):
+		return "int"
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return "float"
+	case kind == reflect.Bool:
+		return "bool"
+	case kind == reflect.String:
+		return "string"
+	case kind == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "bytes"
+	}
+	return ""
+}
+
+// ------- Constraints ------- //
+
+type lessThan struct {
+	lesser  string
+	greater string
+}
+
+// LessThan requires the numeric value of 'lesser' to be strictly less
+// than the value of 'greater'. Keys are global items or paths like
+// 'pool.min_conns'. The constraint holds if any of the keys is absent.
+func LessThan(lesser, greater string) Constraint {
+	return &lessThan{lesser, greater}
+}
+
+func (c *lessThan) Check(conf *Conf) error {
+	vals, err := constraintValues(conf, c.lesser, c.greater)
+	if err != nil || vals == nil {
+		return err
+	}
+
+	if vals[0] >= vals[1] {
+		return goutils.NewErr("'%s' (%v) must be less than '%s' (%v)",
+			c.lesser, vals[0], c.greater, vals[1])
+	}
+
+	return nil
+}
+
+type sumAtMost struct {
+	limit float64
+	keys  []string
+}
+
+// SumAtMost requires the sum of the numeric values of keys not to exceed
+// limit. Keys are global items or paths like 'pool.read_ratio', and
+// absent keys are not counted.
+func SumAtMost(limit float64, keys ...string) Constraint {
+	return &sumAtMost{limit, keys}
+}
+
+func (c *sumAtMost) Check(conf *Conf) error {
+	var sum float64
+	for _, key := range c.keys {
+		vals, err := constraintValues(conf, key)
+		if err != nil {
+			return err
+		}
+		if vals != nil {
+			sum += vals[0]
+		}
+	}
+
+	if sum > c.limit {
+		return goutils.NewErr("sum of '%s' (%v) must be at most %v",
+			strings.Join(c.keys, "', '"), sum, c.limit)
+	}
+
+	return nil
+}
+
+// constraintValues fetches keys as floats from the global section or by
+// path. It returns nil values if any key is absent.
+func constraintValues(conf *Conf, keys ...string) ([]float64, error) {
+	global := conf.sections[_GLOBAL]
+	vals := make([]float64, len(keys))
+	for idx, key := range keys {
+		item, ok := conf.lookup(global, key)
+		if !ok {
+			return nil, nil
+		}
+
+		val, err := item.ToFloat()
+		if err != nil {
+			return nil, goutils.NewErr("'%s' must be a number, value: %s", key, item.val)
+		}
+		vals[idx] = val
+	}
+
+	return vals, nil
+}
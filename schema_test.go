@@ -0,0 +1,171 @@
+/**
+ * Unit test cases for Schema
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 10:40:12
+ */
+
+package goconf
+
+import (
+	"testing"
+)
+
+func TestValidateConstraintsOk(t *testing.T) {
+	conf, buf := genConf("min_conns: 5\nmax_conns: 10\nread: 0.4\nwrite: 0.6")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	schema := NewSchema().Constrain(
+		LessThan("min_conns", "max_conns"),
+		SumAtMost(1.0, "read", "write", "absent"),
+		LessThan("min_conns", "absent"),
+	)
+	if errs := conf.Validate(schema); len(errs) != 0 {
+		t.Errorf("should be valid, errs: %v", errs)
+	}
+}
+
+func TestValidateConstraintsErr(t *testing.T) {
+	conf, buf := genConf("min_conns: 10\nmax_conns: 10\nread: 0.5\nwrite: abc")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	schema := NewSchema().Constrain(
+		LessThan("min_conns", "max_conns"),
+		SumAtMost(1.0, "read", "write"),
+	)
+	if errs := conf.Validate(schema); len(errs) != 2 {
+		t.Errorf("should report 2 violations, errs: %v", errs)
+	}
+}
+
+func TestValidateSections(t *testing.T) {
+	conf, buf := genConf("a: 1\n[server]\nhost: h\nport: 80\n[cache]\nsize: 1")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	schema := NewSchema()
+	schema.Section("server").Required().ItemCount(1, 2)
+	schema.Section("metrics").Optional().ItemCount(1, 0)
+	if errs := conf.Validate(schema); len(errs) != 0 {
+		t.Errorf("should be valid, errs: %v", errs)
+	}
+
+	schema.Section("database").Required()
+	schema.Section("cache").ItemCount(2, 0)
+	if errs := conf.Validate(schema); len(errs) != 2 {
+		t.Errorf("should report 2 violations, errs: %v", errs)
+	}
+
+	// The items of a missing section are reported by the section
+	schema = NewSchema()
+	schema.Section("database").Required()
+	schema.Item("database.host").Required()
+	schema.Item("database.port").Type("uint").Required()
+	if errs := conf.Validate(schema); len(errs) != 1 || errs[0].Error() != "missing required section 'database'" {
+		t.Errorf("should report the missing section only, errs: %v", errs)
+	}
+}
+
+func TestValidateItems(t *testing.T) {
+	conf, buf := genConf("name: app\nport: 8080\nratio: 50%\n[db]\nhosts: a b c\nports: 1 x\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	schema := NewSchema()
+	schema.Item("name").Type("string").Required()
+	schema.Item("port").Type("uint").Required()
+	schema.Item("ratio").Type("percent")
+	schema.Item("timeout").Type("duration")
+	schema.Item("db.hosts").Type("string").Array(1, 3)
+	if errs := conf.Validate(schema); len(errs) != 0 {
+		t.Errorf("should be valid, errs: %v", errs)
+	}
+
+	schema.Item("workers").Required()
+	schema.Item("name").Type("int")
+	schema.Item("db.hosts").Array(4, 0)
+	schema.Item("db.ports").Type("int").Array(0, 0)
+	errs := conf.Validate(schema)
+	if len(errs) != 4 {
+		t.Fatalf("should report 4 violations, errs: %v", errs)
+	}
+	if ErrorCode(errs[0]) != E_TYPE_INT || ErrorCode(errs[3]) != E_TYPE_INT_ARRAY {
+		t.Errorf("need type errors, errs: %v", errs)
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	type config struct {
+		Name    string
+		MaxConn uint16
+		Debug   bool `goconf:"optional"`
+		Ratios  []float64
+		DB      struct {
+			Hosts []string
+			Port  int
+		}
+	}
+	schema, err := SchemaOf(&config{})
+	if err != nil {
+		t.Fatalf("failed to derive a schema, err: %s", err)
+	}
+
+	conf, buf := genConf("name: app\nmax_conn: 10\nratios: 0.5 1\n[DB]\nhosts: a b\nport: 3306\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if errs := conf.Validate(schema); len(errs) != 0 {
+		t.Errorf("should be valid, errs: %v", errs)
+	}
+
+	conf, buf = genConf("name: app\nmax_conn: -1\nratios: a\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	// max_conn and ratios are malformed, and db is missing, whose items
+	// aren't reported again
+	if errs := conf.Validate(schema); len(errs) != 3 {
+		t.Errorf("should report 3 violations, errs: %v", errs)
+	}
+
+	if _, err := SchemaOf(config{}); err == nil {
+		t.Errorf("need an error for a non-pointer")
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"sections": {"db": {"required": true, "min_items": 1}, "cache": {"required": true}},
+		"items": {
+			"db.port": {"type": "uint", "required": true},
+			"db.hosts": {"type": "string", "array": true, "max_len": 1},
+			"name": {"type": "string"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse the schema, err: %s", err)
+	}
+
+	conf, buf := genConf("[db]\nport: x\nhosts: a b\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	// cache is missing, db.hosts is too long and db.port is malformed
+	errs := conf.Validate(schema)
+	if len(errs) != 3 || ErrorCode(errs[2]) != E_TYPE_UINT {
+		t.Errorf("should report 3 violations, errs: %v", errs)
+	}
+
+	if _, err := ParseSchema([]byte(`{"items": {"a": {"type": "complex"}}}`)); err == nil {
+		t.Errorf("need an error for an unknown type")
+	}
+	if _, err := ParseSchema([]byte(`{`)); err == nil {
+		t.Errorf("need an error for malformed JSON")
+	}
+}
@@ -0,0 +1,120 @@
+/**
+ * Secret references keep secrets in secret managers, e.g. Vault or AWS
+ * Secrets Manager, while a single Load still fills them in. A value
+ * 'scheme://path#field' is resolved while parsing by the SecretResolver
+ * registered for the scheme by WithSecretResolver.
+ *
+ *      e.g. config file:
+ *          > db_password: vault://secret/app#password
+ *          > api_token: sm://prod/api#token
+ *
+ *          conf := New("app.conf",
+ *              WithSecretResolver("vault", vaultResolver),
+ *              WithSecretResolver("sm", smResolver))
+ *
+ *  Values of schemes without resolvers are kept as they are.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 05:40:12
+ */
+
+package goconf
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	_SCHEME_SEP = "://"
+	_FIELD_SEP  = "#"
+)
+
+// A SecretRef is a reference to a secret, e.g. 'vault://secret/app#password'
+// is of scheme "vault", path "secret/app" and field "password". Field is ""
+// if the reference has no '#'.
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Field  string
+}
+
+func (ref SecretRef) String() string {
+	s := ref.Scheme + _SCHEME_SEP + ref.Path
+	if ref.Field != "" {
+		s += _FIELD_SEP + ref.Field
+	}
+	return s
+}
+
+// A SecretResolver reads the secret a reference refers to.
+type SecretResolver interface {
+	Resolve(ref SecretRef) (string, error)
+}
+
+// A ContextSecretResolver is a SecretResolver whose reads respect the
+// cancellation and deadline of the context of ParseContext.
+type ContextSecretResolver interface {
+	SecretResolver
+	ResolveContext(ctx context.Context, ref SecretRef) (string, error)
+}
+
+// SecretResolverFunc adapts a function to a SecretResolver.
+type SecretResolverFunc func(ref SecretRef) (string, error)
+
+func (f SecretResolverFunc) Resolve(ref SecretRef) (string, error) {
+	return f(ref)
+}
+
+// parseSecretRef parses val as 'scheme://path#field'. ok is false if val
+// isn't a reference.
+func parseSecretRef(val string) (ref SecretRef, ok bool) {
+	idx := strings.Index(val, _SCHEME_SEP)
+	if idx <= 0 {
+		return ref, false
+	}
+
+	ref.Scheme, ref.Path = val[:idx], val[idx+len(_SCHEME_SEP):]
+	if idx := strings.LastIndex(ref.Path, _FIELD_SEP); idx >= 0 {
+		ref.Path, ref.Field = ref.Path[:idx], ref.Path[idx+1:]
+	}
+	return ref, true
+}
+
+// resolveSecrets replaces secret references of the items by the secrets.
+func (conf *Conf) resolveSecrets(resolvers map[string]SecretResolver) error {
+	return conf.Walk(func(section string, item *Item) error {
+		ref, ok := parseSecretRef(item.val)
+		if !ok {
+			return nil
+		}
+		r := resolvers[ref.Scheme]
+		if r == nil {
+			return nil
+		}
+
+		secret, err := conf.resolveSecret(r, ref)
+		if err != nil {
+			return &ParseError{Code: E_SECRET, File: conf.filePath,
+				Msg: fmt.Sprintf("failed to resolve '%s' by %s: %s", itemPath(section, item.key), ref, err), Err: err}
+		}
+		item.raw = item.Raw()
+		item.val = secret
+		item.origin.secret = true
+		return nil
+	})
+}
+
+// resolveSecret resolves ref by r, by the context of parsing if r is a
+// ContextSecretResolver.
+func (conf *Conf) resolveSecret(r SecretResolver, ref SecretRef) (string, error) {
+	ctx := conf.context()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if cr, ok := r.(ContextSecretResolver); ok {
+		return cr.ResolveContext(ctx, ref)
+	}
+	return r.Resolve(ref)
+}
@@ -0,0 +1,75 @@
+package goconf
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretRefs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("home: http://example.com\n[db]\npassword: vault://secret/app#password\n"), 0644)
+
+	var refs []SecretRef
+	vault := SecretResolverFunc(func(ref SecretRef) (string, error) {
+		refs = append(refs, ref)
+		if ref.Path != "secret/app" {
+			return "", errors.New("no such secret")
+		}
+		return "s3cret", nil
+	})
+
+	configObj := struct {
+		Home string
+		Db   struct{ Password string }
+	}{}
+	if err := Load(&configObj, path, WithSecretResolver("vault", vault)); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if configObj.Home != "http://example.com" || configObj.Db.Password != "s3cret" {
+		t.Errorf("not expected obj: %+v", configObj)
+	}
+	if len(refs) != 1 || refs[0] != (SecretRef{"vault", "secret/app", "password"}) {
+		t.Errorf("not expected refs: %v", refs)
+	}
+
+	os.WriteFile(path, []byte("token: vault://secret/other\n"), 0644)
+	err := New(path, WithSecretResolver("vault", vault)).Parse()
+	if ErrorCode(err) != E_SECRET {
+		t.Errorf("need a secret error, err: %v", err)
+	}
+}
+
+type ctxResolver struct{ ctx context.Context }
+
+func (r *ctxResolver) Resolve(ref SecretRef) (string, error) {
+	return "", errors.New("need ResolveContext")
+}
+
+func (r *ctxResolver) ResolveContext(ctx context.Context, ref SecretRef) (string, error) {
+	r.ctx = ctx
+	return "s3cret", nil
+}
+
+func TestParseContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(path, []byte("[db]\npassword: vault://secret/app#password\n"), 0644)
+
+	r := &ctxResolver{}
+	conf := New(path, WithSecretResolver("vault", r))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := conf.ParseContext(ctx); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if v, _ := conf.GetStringFrom("db", "password"); v != "s3cret" || r.ctx != ctx {
+		t.Errorf("secret isn't resolved by the context, val: %s", v)
+	}
+
+	cancel()
+	if err := New("conf_sample.conf").ParseContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("need a canceled error, err: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+/**
+ * Load dynamically named sections into a map of structs.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 09:12:40
+ */
+
+package goconf
+
+import (
+	"reflect"
+	"strings"
+)
+
+const (
+	_TAG_SECTIONS       = "sections="
+	_SECTION_PREFIX_SEP = ':'
+)
+
+// isSectionMap reports whether a field of type t collects sections, i.e.
+// t is a map from string to a struct or a pointer to a struct.
+func isSectionMap(t reflect.Type) bool {
+	if t.Kind() != reflect.Map || t.Key().Kind() != reflect.String {
+		return false
+	}
+
+	ele := t.Elem()
+	if ele.Kind() == reflect.Ptr {
+		ele = ele.Elem()
+	}
+
+	return ele.Kind() == reflect.Struct && converterOf(ele) == nil
+}
+
+// sectionPrefixes returns the prefixes of sections collected by a map
+// field. It's the `goconf:"sections=name"` tag if any, or else the names
+// the field may be mapped to, also without a trailing 's'. So a field
+// 'Backends' collects sections like '[backend:cache1]'.
+func sectionPrefixes(field *reflect.StructField) []string {
+	if prefix, ok := sectionsTag(field); ok {
+		return []string{prefix}
+	}
+
+	var prefixes []string
+	for _, name := range optNameCandidates(field.Name) {
+		prefixes = append(prefixes, name)
+		if singular := strings.TrimSuffix(name, "s"); singular != name && singular != "" {
+			prefixes = append(prefixes, singular)
+		}
+	}
+
+	return prefixes
+}
+
+// sectionPrefix returns the prefix of sections written for a map field:
+// the prefix of the tag, or the field name like 'a_example_field' without a
+// trailing 's'.
+func sectionPrefix(field *reflect.StructField) string {
+	if prefix, ok := sectionsTag(field); ok {
+		return prefix
+	}
+
+	name := keyName(field.Name)
+	if singular := strings.TrimSuffix(name, "s"); singular != "" {
+		return singular
+	}
+	return name
+}
+
+// sectionsTag returns the prefix of a `goconf:"sections=name"` tag.
+func sectionsTag(field *reflect.StructField) (string, bool) {
+	for _, opt := range strings.Split(field.Tag.Get(_TAG), ",") {
+		opt = strings.TrimSpace(opt)
+		if strings.HasPrefix(opt, _TAG_SECTIONS) {
+			return opt[len(_TAG_SECTIONS):], true
+		}
+	}
+
+	return "", false
+}
+
+// loadSectionMap loads every section named 'prefix:name' into the map
+// field by key 'name'. The first prefix with sections is used.
+func (l *loader) loadSectionMap(fieldMeta *reflect.StructField, fieldValue *reflect.Value) error {
+	var names []string
+	var prefix string
+	for _, p := range sectionPrefixes(fieldMeta) {
+		prefix = p + string(_SECTION_PREFIX_SEP)
+		for _, name := range l.conf.Sections(false) {
+			if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+				names = append(names, name)
+			}
+		}
+		if len(names) != 0 {
+			break
+		}
+	}
+
+	if len(names) == 0 {
+		if l.opts.requireAll && !hasTagOpt(fieldMeta, _TAG_OPTIONAL) {
+			l.missing = append(l.missing, l.prefix+fieldMeta.Name)
+		}
+		return nil
+	}
+
+	mapType := fieldValue.Type()
+	eleType := mapType.Elem()
+	isPtr := eleType.Kind() == reflect.Ptr
+	if isPtr {
+		eleType = eleType.Elem()
+	}
+
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.MakeMapWithSize(mapType, len(names)))
+	}
+
+	for _, name := range names {
+		key := name[len(prefix):]
+		ptr := reflect.New(eleType)
+		ele := ptr.Elem()
+		if err := l.loadStruct(fieldMeta.Name+"."+key, name, &ele); err != nil {
+			return err
+		}
+
+		if isPtr {
+			fieldValue.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), ptr)
+		} else {
+			fieldValue.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), ele)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+/**
+ * Snapshots of a conf, so a conf changed in place, e.g. by an overlay or
+ * MergeMap, can be reverted to the last known-good state if it fails
+ * validation.
+ *
+ *      e.g.
+ *          snap := conf.Snapshot()
+ *          conf.MergeMap(update)
+ *          if errs := conf.Validate(schema); len(errs) != 0 {
+ *              conf.Rollback(snap)
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 15:20:48
+ */
+
+package goconf
+
+import (
+	"errors"
+)
+
+// A Snapshot is the state of a conf, taken by Conf.Snapshot.
+type Snapshot struct {
+	source *Conf // conf taken
+	state  *Conf // a copy of the state
+}
+
+// Snapshot returns the current state of conf: its items, sections,
+// defaults, options and current section.
+func (conf *Conf) Snapshot() *Snapshot {
+	return &Snapshot{source: conf, state: conf.Clone()}
+}
+
+// Rollback reverts conf to the state of snap, which must be taken from
+// conf. All the state is replaced at once, and the same snapshot can be
+// rolled back to again.
+func (conf *Conf) Rollback(snap *Snapshot) error {
+	if snap == nil || snap.source != conf {
+		return errors.New("the snapshot isn't taken from this conf")
+	}
+
+	state := snap.state.Clone()
+	conf.setItems(state)
+	conf.cur = state.cur
+	conf.defaults = state.defaults
+	conf.opts = state.opts
+	return nil
+}
+
+// setItems replaces the sections and items of conf by the ones of state.
+func (conf *Conf) setItems(state *Conf) {
+	conf.sections = state.sections
+	conf.annotations = state.annotations
+	conf.pending = state.pending
+	conf.itemCount = state.itemCount
+	conf.warnings, conf.warned = state.warnings, state.warned
+}
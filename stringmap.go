@@ -0,0 +1,108 @@
+/**
+ * Inline maps are items whose values are entries of key/value pairs.
+ *
+ *      e.g.
+ *          > labels: env=prod,team=search,region=eu
+ *
+ *          conf.GetStringMap("labels") => map[env:prod region:eu team:search]
+ *
+ *  Entries are separated by ',' and pairs by '=' by default, which
+ *  WithMapSeparators changes. A map field of a config struct, e.g.
+ *  map[string]string or map[string]int, is loaded from an inline map.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:02:51
+ */
+
+package goconf
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+const (
+	_MAP_ENTRY_SEP = ","
+	_MAP_PAIR_SEP  = "="
+)
+
+// WithMapSeparators sets the separators of entries and of the key and
+// value of an entry in inline maps, which are ',' and '=' by default.
+func WithMapSeparators(entrySep, pairSep string) Option {
+	return func(o *options) {
+		o.mapEntrySep, o.mapPairSep = entrySep, pairSep
+	}
+}
+
+// ToStringMapSep parses the value as entries separated by entrySep, whose
+// key and value are separated by pairSep. Empty entries are skipped, and a
+// later entry of a key wins.
+func (item *Item) ToStringMapSep(entrySep, pairSep string) (map[string]string, error) {
+	m := make(map[string]string)
+	for _, entry := range strings.Split(item.val, entrySep) {
+		if entry = strings.Trim(entry, _SPACE_CHARS); entry == "" {
+			continue
+		}
+
+		idx := strings.Index(entry, pairSep)
+		if idx < 0 {
+			return nil, item.typeErr("string map", errors.New("no '"+pairSep+"' in entry '"+entry+"'"))
+		}
+		key := strings.Trim(entry[:idx], _SPACE_CHARS)
+		if key == "" {
+			return nil, item.typeErr("string map", errors.New("no key in entry '"+entry+"'"))
+		}
+		m[key] = strings.Trim(entry[idx+len(pairSep):], _SPACE_CHARS)
+	}
+
+	return m, nil
+}
+
+// GetStringMap returns item 'key' as an inline map, by the separators of
+// conf.
+func (conf *Conf) GetStringMap(key string) (map[string]string, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToStringMapSep(conf.mapSeparators())
+}
+
+func (conf *Conf) mapSeparators() (entrySep, pairSep string) {
+	entrySep, pairSep = conf.opts.mapEntrySep, conf.opts.mapPairSep
+	if entrySep == "" {
+		entrySep = _MAP_ENTRY_SEP
+	}
+	if pairSep == "" {
+		pairSep = _MAP_PAIR_SEP
+	}
+	return entrySep, pairSep
+}
+
+// loadMapField loads a map field with string keys from an inline map. Values
+// are converted as scalar items, e.g. to int for map[string]int.
+func (l *loader) loadMapField(name string, item *Item, fieldValue *reflect.Value) error {
+	typ := fieldValue.Type()
+	if typ.Key().Kind() != reflect.String {
+		return errors.New("not support key type for map: " + typ.Key().String())
+	}
+
+	entries, err := item.ToStringMapSep(l.conf.mapSeparators())
+	if err != nil {
+		return err
+	}
+
+	m := reflect.MakeMapWithSize(typ, len(entries))
+	for key, val := range entries {
+		elem := reflect.New(typ.Elem()).Elem()
+		if err := setScalar(name+"."+key, &Item{key: key, val: val}, &elem); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(key).Convert(typ.Key()), elem)
+	}
+	fieldValue.Set(m)
+
+	return nil
+}
@@ -0,0 +1,150 @@
+/**
+ * TLS settings of a section, read into a ready-to-use tls.Config, so a
+ * service doesn't reimplement loading certificates, e.g.
+ *
+ *      [tls]
+ *      cert_file: server.crt
+ *      key_file: server.key
+ *      ca_file: ca.crt
+ *      min_version: 1.2
+ *      ciphers: TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384
+ *
+ *      e.g.
+ *          tlsConfig, err := conf.GetTLSConfig("tls")
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 16:27:14
+ */
+
+package goconf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chosen0ne/goutils"
+)
+
+// Items of a TLS section.
+const (
+	_TLS_CERT_FILE   = "cert_file"
+	_TLS_KEY_FILE    = "key_file"
+	_TLS_CA_FILE     = "ca_file"
+	_TLS_MIN_VERSION = "min_version"
+	_TLS_CIPHERS     = "ciphers"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// GetTLSConfig returns the tls.Config of section 'sectionName'. All the
+// items are optional, but a certificate needs both cert_file and key_file.
+// The certificates of ca_file verify both servers and clients, so a server
+// requiring client certificates only sets ClientAuth. Relative paths are
+// relative to the config file. min_version is e.g. '1.2' or 'TLS1.2', and
+// ciphers are names of secure cipher suites.
+func (conf *Conf) GetTLSConfig(sectionName string) (*tls.Config, error) {
+	items := make(map[string]*Item)
+	for _, key := range []string{_TLS_CERT_FILE, _TLS_KEY_FILE, _TLS_CA_FILE, _TLS_MIN_VERSION, _TLS_CIPHERS} {
+		item, err := conf.GetItemFrom(sectionName, key)
+		if err != nil && ErrorCode(err) != E_KEY_NOT_FOUND {
+			return nil, err
+		} else if err == nil {
+			items[key] = item
+		}
+	}
+
+	tlsConfig := &tls.Config{}
+	certItem, keyItem := items[_TLS_CERT_FILE], items[_TLS_KEY_FILE]
+	if (certItem == nil) != (keyItem == nil) {
+		return nil, goutils.NewErr("section '%s' needs both '%s' and '%s'", sectionName, _TLS_CERT_FILE, _TLS_KEY_FILE)
+	} else if certItem != nil {
+		certPEM, err := readItemFile(certItem)
+		if err != nil {
+			return nil, err
+		}
+		keyPEM, err := readItemFile(keyItem)
+		if err != nil {
+			return nil, err
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, certItem.typeErr("TLS certificate", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if item := items[_TLS_CA_FILE]; item != nil {
+		caPEM, err := readItemFile(item)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, item.typeErr("CA certificates", errors.New("no certificate in PEM"))
+		}
+		tlsConfig.RootCAs, tlsConfig.ClientCAs = pool, pool
+	}
+
+	if item := items[_TLS_MIN_VERSION]; item != nil {
+		v := strings.TrimPrefix(strings.ToLower(item.val), "tls")
+		version, ok := tlsVersions[strings.TrimPrefix(v, "v")]
+		if !ok {
+			return nil, item.typeErr("TLS version", nil)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if item := items[_TLS_CIPHERS]; item != nil {
+		suites, err := cipherSuites(item)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.CipherSuites = suites
+	}
+
+	return tlsConfig, nil
+}
+
+// readItemFile reads the file of the path of the item. A relative path is
+// relative to the config file of the item.
+func readItemFile(item *Item) ([]byte, error) {
+	path := item.val
+	if !filepath.IsAbs(path) && item.origin.file != "" {
+		path = filepath.Join(filepath.Dir(item.origin.file), path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fileErr(path, err)
+	}
+	return content, nil
+}
+
+// cipherSuites returns the IDs of the cipher suites named by the item.
+// Insecure suites are rejected.
+func cipherSuites(item *Item) ([]uint16, error) {
+	ids := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		ids[suite.Name] = suite.ID
+	}
+
+	names := item.ToStringArray()
+	suites := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := ids[name]
+		if !ok {
+			return nil, item.typeErr("TLS cipher suites", errors.New("unknown or insecure cipher suite "+name))
+		}
+		suites = append(suites, id)
+	}
+	return suites, nil
+}
@@ -0,0 +1,61 @@
+/**
+ * Package tomlconf adds TOML config files to goconf. Importing it registers
+ * the extension '.toml', then Load and Parse work the same with TOML files
+ * as with goconf files.
+ *
+ *      e.g.
+ *          import _ "github.com/chosen0ne/goconf/tomlconf"
+ *
+ *  Tables are sections, and the top-level keys are global items. Arrays are
+ *  array items. Nested tables and arrays of tables aren't supported, as
+ *  goconf has only one level of sections.
+ *
+ *      e.g.
+ *          name = "app"
+ *          ports = [80, 443]
+ *          [db]
+ *          timeout = 1.5
+ *
+ *      is the same as config file:
+ *          > name: app
+ *          > ports: 80 443
+ *          > [db]
+ *          > timeout: 1.5
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 21:40:18
+ */
+
+package tomlconf
+
+import (
+	"github.com/BurntSushi/toml"
+	"github.com/chosen0ne/goconf"
+	"io"
+	"strings"
+)
+
+func init() {
+	goconf.RegisterFormat(".toml", Parse)
+}
+
+// Parse is the goconf.FormatParser of TOML files. Items keep the order of
+// the file.
+func Parse(conf *goconf.Conf, r io.Reader, path string) error {
+	var m map[string]interface{}
+	md, err := toml.NewDecoder(r).Decode(&m)
+	if err != nil {
+		return &goconf.ParseError{
+			Code: goconf.E_FORMAT,
+			File: path,
+			Msg:  "invalid TOML: " + err.Error(),
+			Err:  err,
+		}
+	}
+
+	keys := make([]string, len(md.Keys()))
+	for idx, key := range md.Keys() {
+		keys[idx] = strings.Join(key, ".")
+	}
+	return conf.MergeMapInOrder(m, keys)
+}
@@ -0,0 +1,182 @@
+/**
+ * Values of library types, e.g. IP addresses. Each type has getters, and
+ * fields of the type are loaded by its converter, so malformed values fail
+ * at startup. Converters of other types, e.g. custom enums or decimals, are
+ * registered by RegisterConverter.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 09:20:14
+ */
+
+package goconf
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// A converter converts an item to a value of a field type.
+type converter func(item *Item) (interface{}, error)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// converters convert items to values of field types, which the loader
+// takes before kinds. They're guarded by convertersLock, as converters may
+// be registered while loading.
+var convertersLock sync.RWMutex
+var converters = map[reflect.Type]converter{
+	durationType: func(item *Item) (interface{}, error) {
+		return item.ToDuration()
+	},
+	reflect.TypeOf(net.IP{}): func(item *Item) (interface{}, error) {
+		return item.ToIP()
+	},
+	reflect.TypeOf(net.IPNet{}): func(item *Item) (interface{}, error) {
+		ipNet, err := item.ToCIDR()
+		if err != nil {
+			return nil, err
+		}
+		return *ipNet, nil
+	},
+	reflect.TypeOf(&net.IPNet{}): func(item *Item) (interface{}, error) {
+		return item.ToCIDR()
+	},
+	reflect.TypeOf(url.URL{}): func(item *Item) (interface{}, error) {
+		u, err := item.ToURL()
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	},
+	reflect.TypeOf(&url.URL{}): func(item *Item) (interface{}, error) {
+		return item.ToURL()
+	},
+	reflect.TypeOf(&regexp.Regexp{}): func(item *Item) (interface{}, error) {
+		return item.ToRegexp()
+	},
+}
+
+// RegisterConverter makes the loader fill fields of type typ by conv, which
+// converts a value to typ, e.g.
+//
+//	RegisterConverter(reflect.TypeOf(Color(0)), func(s string) (interface{}, error) {
+//	    return parseColor(s)
+//	})
+//
+// It replaces the converter of typ, if any, including the builtin ones.
+func RegisterConverter(typ reflect.Type, conv func(string) (interface{}, error)) {
+	convertersLock.Lock()
+	defer convertersLock.Unlock()
+
+	converters[typ] = func(item *Item) (interface{}, error) {
+		val, err := conv(item.val)
+		if err != nil {
+			return nil, item.typeErr(typ.String(), err)
+		}
+		if val == nil {
+			return reflect.Zero(typ).Interface(), nil
+		}
+		if !reflect.TypeOf(val).AssignableTo(typ) {
+			return nil, item.typeErr(typ.String(), errors.New("converted to "+reflect.TypeOf(val).String()))
+		}
+		return val, nil
+	}
+}
+
+// converterOf returns the converter of typ, or nil.
+func converterOf(typ reflect.Type) converter {
+	convertersLock.RLock()
+	defer convertersLock.RUnlock()
+
+	return converters[typ]
+}
+
+// ToIP parses the value as an IPv4 or IPv6 address.
+func (item *Item) ToIP() (net.IP, error) {
+	ip := net.ParseIP(item.val)
+	if ip == nil {
+		return nil, item.typeErr("ip", errors.New("invalid IP address"))
+	}
+	return ip, nil
+}
+
+// ToCIDR parses the value as a network like '10.0.0.0/8'.
+func (item *Item) ToCIDR() (*net.IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(item.val)
+	if err != nil {
+		return nil, item.typeErr("cidr", err)
+	}
+	return ipNet, nil
+}
+
+// ToURL parses the value as an absolute URL, which has a scheme and a host,
+// e.g. 'https://example.com/api'.
+func (item *Item) ToURL() (*url.URL, error) {
+	u, err := url.Parse(item.val)
+	if err != nil {
+		return nil, item.typeErr("url", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, item.typeErr("url", errors.New("no scheme or host"))
+	}
+	return u, nil
+}
+
+// ToRegexp compiles the value as a regular expression.
+func (item *Item) ToRegexp() (*regexp.Regexp, error) {
+	re, err := regexp.Compile(item.val)
+	if err != nil {
+		return nil, item.typeErr("regexp", err)
+	}
+	return re, nil
+}
+
+// ToDuration parses the value as a duration like '1m30s'.
+func (item *Item) ToDuration() (time.Duration, error) {
+	d, err := time.ParseDuration(item.val)
+	if err != nil {
+		return 0, item.typeErr("duration", err)
+	}
+	return d, nil
+}
+
+func (conf *Conf) GetIP(key string) (net.IP, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToIP()
+}
+
+func (conf *Conf) GetCIDR(key string) (*net.IPNet, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToCIDR()
+}
+
+func (conf *Conf) GetURL(key string) (*url.URL, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToURL()
+}
+
+func (conf *Conf) GetRegexp(key string) (*regexp.Regexp, error) {
+	item, err := conf.GetItem(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return item.ToRegexp()
+}
@@ -0,0 +1,195 @@
+/**
+ * Unit test cases for values of library types
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 09:20:14
+ */
+
+package goconf
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIPAndCIDR(t *testing.T) {
+	conf, buf := genConf("addr: 10.0.0.1\naddr6: ::1\nnet: 10.0.0.0/8\nbad: 10.0.0.300\n[s]\nallow: 192.168.0.0/16\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	if ip, err := conf.GetIP("addr"); err != nil || !ip.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("not expected addr: %s, err: %v", ip, err)
+	}
+	if ip, err := conf.GetIP("addr6"); err != nil || !ip.Equal(net.IPv6loopback) {
+		t.Errorf("not expected addr6: %s, err: %v", ip, err)
+	}
+	if n, err := conf.GetCIDR("net"); err != nil || !n.Contains(net.IPv4(10, 1, 2, 3)) {
+		t.Errorf("not expected net: %s, err: %v", n, err)
+	}
+	for _, key := range []string{"bad", "net"} {
+		if _, err := conf.GetIP(key); ErrorCode(err) != E_TYPE {
+			t.Errorf("need a type error for '%s', err: %v", key, err)
+		}
+	}
+
+	configObj := struct {
+		Addr net.IP
+		Net  net.IPNet
+		S    struct{ Allow *net.IPNet }
+	}{}
+	if err := loadConf(&configObj, conf, nil); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if configObj.Addr.String() != "10.0.0.1" || configObj.Net.String() != "10.0.0.0/8" ||
+		configObj.S.Allow.String() != "192.168.0.0/16" {
+		t.Errorf("not expected obj: %+v", configObj)
+	}
+
+	bad := struct{ Bad net.IP }{}
+	if err := loadConf(&bad, conf, nil); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error, err: %v", err)
+	}
+}
+
+func TestURL(t *testing.T) {
+	conf, buf := genConf("api: https://example.com:8443/v1?x=1\nproxy: http://proxy\nrel: /path\nbad: http://a b\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	if u, err := conf.GetURL("api"); err != nil || u.Host != "example.com:8443" || u.Path != "/v1" {
+		t.Errorf("not expected api: %v, err: %v", u, err)
+	}
+	for _, key := range []string{"rel", "bad"} {
+		if _, err := conf.GetURL(key); ErrorCode(err) != E_TYPE {
+			t.Errorf("need a type error for '%s', err: %v", key, err)
+		}
+	}
+
+	configObj := struct {
+		Api   url.URL
+		Proxy *url.URL
+	}{}
+	if err := loadConf(&configObj, conf, nil); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if configObj.Api.Scheme != "https" || configObj.Proxy.Host != "proxy" {
+		t.Errorf("not expected obj: %+v", configObj)
+	}
+
+	bad := struct{ Rel *url.URL }{}
+	if err := loadConf(&bad, conf, nil); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error, err: %v", err)
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	conf, buf := genConf("route: ^/api/v[0-9]+/\nbad: a(b\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	if re, err := conf.GetRegexp("route"); err != nil || !re.MatchString("/api/v2/users") {
+		t.Errorf("not expected route: %v, err: %v", re, err)
+	}
+	if _, err := conf.GetRegexp("bad"); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error, err: %v", err)
+	}
+
+	configObj := struct{ Route *regexp.Regexp }{}
+	if err := loadConf(&configObj, conf, nil); err != nil || configObj.Route.String() != "^/api/v[0-9]+/" {
+		t.Errorf("not expected obj: %+v, err: %v", configObj, err)
+	}
+	bad := struct{ Bad *regexp.Regexp }{}
+	if err := loadConf(&bad, conf, nil); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error, err: %v", err)
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	conf, buf := genConf("labels: env=prod, team=search,region=eu,\nlimits: a=1,b=2\nbad: a=1,b\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	conf.SetGlobalSection()
+
+	expected := map[string]string{"env": "prod", "team": "search", "region": "eu"}
+	if m, err := conf.GetStringMap("labels"); err != nil || !reflect.DeepEqual(m, expected) {
+		t.Errorf("not expected labels: %v, err: %v", m, err)
+	}
+	if _, err := conf.GetStringMap("bad"); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error, err: %v", err)
+	}
+
+	configObj := struct {
+		Labels map[string]string
+		Limits map[string]int
+	}{}
+	if err := loadConf(&configObj, conf, nil); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if !reflect.DeepEqual(configObj.Labels, expected) || configObj.Limits["b"] != 2 {
+		t.Errorf("not expected obj: %+v", configObj)
+	}
+	bad := struct{ Labels map[string]int }{}
+	if err := loadConf(&bad, conf, nil); ErrorCode(err) != E_TYPE_INT {
+		t.Errorf("need a type error, err: %v", err)
+	}
+
+	conf, buf = genConf("hosts: a:1;b:2\n")
+	conf.opts.mapEntrySep, conf.opts.mapPairSep = ";", ":"
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+	if m, err := conf.GetStringMap("hosts"); err != nil || m["b"] != "2" {
+		t.Errorf("not expected hosts: %v, err: %v", m, err)
+	}
+}
+
+type testColor int
+
+func TestRegisterConverter(t *testing.T) {
+	colors := map[string]testColor{"red": 1, "green": 2}
+	RegisterConverter(reflect.TypeOf(testColor(0)), func(s string) (interface{}, error) {
+		c, ok := colors[s]
+		if !ok {
+			return nil, errors.New("unknown color")
+		}
+		return c, nil
+	})
+	RegisterConverter(reflect.TypeOf(time.Time{}), func(s string) (interface{}, error) {
+		return s, nil
+	})
+	t.Cleanup(func() {
+		delete(converters, reflect.TypeOf(testColor(0)))
+		delete(converters, reflect.TypeOf(time.Time{}))
+	})
+
+	conf, buf := genConf("color: green\nbad: blue\nat: now\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	configObj := struct{ Color testColor }{}
+	if err := loadConf(&configObj, conf, nil); err != nil || configObj.Color != 2 {
+		t.Errorf("not expected obj: %+v, err: %v", configObj, err)
+	}
+	bad := struct{ Bad testColor }{}
+	if err := loadConf(&bad, conf, nil); ErrorCode(err) != E_TYPE || !strings.Contains(err.Error(), "testColor") {
+		t.Errorf("need a type error, err: %v", err)
+	}
+	at := struct{ At time.Time }{}
+	if err := loadConf(&at, conf, nil); ErrorCode(err) != E_TYPE {
+		t.Errorf("need a type error for a value of another type, err: %v", err)
+	}
+}
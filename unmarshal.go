@@ -0,0 +1,77 @@
+/**
+ * Unmarshalers let applications decode their own value formats. A field
+ * whose type, or pointer to it, implements Unmarshaler is decoded from its
+ * item by UnmarshalConf, and one implementing SectionUnmarshaler from its
+ * section by UnmarshalConfSection, instead of by the loader.
+ *
+ *      e.g.
+ *          type Level int
+ *
+ *          func (l *Level) UnmarshalConf(item *goconf.Item) error {
+ *              switch item.ToString() {
+ *              case "debug":
+ *                  *l = 0
+ *              case "info":
+ *                  *l = 1
+ *              default:
+ *                  return fmt.Errorf("unknown level '%s'", item.ToString())
+ *              }
+ *              return nil
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:41:27
+ */
+
+package goconf
+
+import (
+	"reflect"
+)
+
+// An Unmarshaler decodes itself from an item.
+type Unmarshaler interface {
+	UnmarshalConf(item *Item) error
+}
+
+// A SectionUnmarshaler decodes itself from section 'name' of conf, e.g. by
+// conf.GetIntFrom(name, "port").
+type SectionUnmarshaler interface {
+	UnmarshalConfSection(conf *Conf, name string) error
+}
+
+var (
+	unmarshalerType        = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	sectionUnmarshalerType = reflect.TypeOf((*SectionUnmarshaler)(nil)).Elem()
+)
+
+// implementer returns the value of the field implementing iface, i.e. the
+// field or its address, or nil if neither does. A nil pointer field is
+// allocated.
+func implementer(fieldValue *reflect.Value, iface reflect.Type) interface{} {
+	typ := fieldValue.Type()
+	if reflect.PointerTo(typ).Implements(iface) {
+		return fieldValue.Addr().Interface()
+	}
+	if typ.Kind() != reflect.Ptr || !typ.Implements(iface) {
+		return nil
+	}
+
+	if fieldValue.IsNil() {
+		fieldValue.Set(reflect.New(typ.Elem()))
+	}
+	return fieldValue.Interface()
+}
+
+// unmarshalSection decodes a field from section 'name' by u. The items of
+// the section are taken as consumed.
+func (l *loader) unmarshalSection(u SectionUnmarshaler, name string) error {
+	if err := l.conf.loadSection(name); err != nil {
+		return err
+	}
+	for _, item := range l.conf.sections[name].items() {
+		l.used[item] = true
+	}
+
+	return u.UnmarshalConfSection(l.conf, name)
+}
@@ -0,0 +1,82 @@
+/**
+ * Unit test cases for unmarshalers
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 10:41:27
+ */
+
+package goconf
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalConf(item *Item) error {
+	switch item.ToString() {
+	case "debug":
+		*l = 1
+	case "info":
+		*l = 2
+	default:
+		return errors.New("unknown level " + item.ToString())
+	}
+	return nil
+}
+
+// testEndpoint is decoded from a single item 'host:port', though it's a struct
+type testEndpoint struct {
+	Host, Port string
+}
+
+func (e *testEndpoint) UnmarshalConf(item *Item) error {
+	e.Host, e.Port, _ = strings.Cut(item.ToString(), ":")
+	return nil
+}
+
+type testPool struct {
+	addrs []string
+}
+
+func (p *testPool) UnmarshalConfSection(conf *Conf, name string) error {
+	items, err := conf.SectionItems(name)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		p.addrs = append(p.addrs, item.ToString())
+	}
+	sort.Strings(p.addrs)
+	return nil
+}
+
+func TestUnmarshalers(t *testing.T) {
+	conf, buf := genConf("level: info\nendpoint: db:3306\n[pool]\nb: 10.0.0.2\na: 10.0.0.1\n")
+	if err := conf.parse(buf); err != nil {
+		t.Fatalf("failed to parse, err: %s", err)
+	}
+
+	configObj := struct {
+		Level    testLevel
+		Endpoint *testEndpoint
+		Pool     testPool
+	}{}
+	opts := []Option{DisallowUnknownKeys()}
+	if err := loadConf(&configObj, conf, opts); err != nil {
+		t.Fatalf("failed to load, err: %s", err)
+	}
+	if configObj.Level != 2 || configObj.Endpoint.Host != "db" || configObj.Endpoint.Port != "3306" ||
+		strings.Join(configObj.Pool.addrs, ",") != "10.0.0.1,10.0.0.2" {
+		t.Errorf("not expected obj: %+v", configObj)
+	}
+
+	conf, buf = genConf("level: trace\n")
+	conf.parse(buf)
+	if err := loadConf(&configObj, conf, nil); err == nil || err.Error() != "unknown level trace" {
+		t.Errorf("need the error of the unmarshaler, err: %v", err)
+	}
+}
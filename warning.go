@@ -0,0 +1,85 @@
+/**
+ * Warnings of recoverable issues, which don't fail parsing or loading but
+ * are likely mistakes: empty values skipped, keys repeated in a file, and
+ * items which no field consumes.
+ *
+ *      e.g.
+ *          for _, w := range conf.Warnings() {
+ *              log.Print(w)
+ *          }
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/17 17:06:31
+ */
+
+package goconf
+
+import (
+	"slices"
+)
+
+// A Warning is a recoverable issue of an item.
+type Warning struct {
+	Source string // where the item is, e.g. 'app.conf:12', empty if unknown
+	Key    string // path of the item, e.g. 'db.port'
+	Msg    string
+}
+
+func (w Warning) String() string {
+	s := "'" + w.Key + "': " + w.Msg
+	if w.Source != "" {
+		s = w.Source + ": " + s
+	}
+	return s
+}
+
+// Warnings returns the warnings of parsing conf, in the order found. The
+// warnings of loading config objects, e.g. unknown keys, are returned by
+// CollectWarnings, as loads don't change the conf.
+func (conf *Conf) Warnings() []Warning {
+	return slices.Clone(conf.warnings)
+}
+
+// SkipEmptyValues skips items with empty values, e.g. 'port:', as warnings,
+// instead of failing with E_PARSE_EMPTY_VALUE.
+func SkipEmptyValues() Option {
+	return func(o *options) {
+		o.skipEmpty = true
+	}
+}
+
+// CollectWarnings stores the warnings of parsing and loading into warnings,
+// see Conf.Warnings.
+func CollectWarnings(warnings *[]Warning) Option {
+	return func(o *options) {
+		o.warnings = warnings
+	}
+}
+
+// warn adds a warning of item 'key' at o. A warning found again, e.g. by
+// parsing a section of WithLazySections again, is added once.
+func (conf *Conf) warn(o origin, key, msg string) {
+	w := Warning{Source: o.String(), Key: key, Msg: msg}
+	if _, ok := conf.warned[w]; ok {
+		return
+	}
+	if conf.warned == nil {
+		conf.warned = make(map[Warning]struct{})
+	}
+	conf.warned[w] = struct{}{}
+	conf.warnings = append(conf.warnings, w)
+}
+
+// warnDuplicate adds a warning of item 'key' repeated in a file at o, where
+// old is the item set before, unless the values are collected or merged.
+func (conf *Conf) warnDuplicate(o origin, key string, old *Item) {
+	switch conf.opts.duplicateKeys {
+	case DuplicateLastWins:
+		if conf.opts.mergeStrategies[key] != MergeReplace {
+			return
+		}
+		conf.warn(o, key, "duplicate key overrides the value at "+old.Source())
+	case DuplicateFirstWins:
+		conf.warn(o, key, "duplicate key is dropped for the value at "+old.Source())
+	}
+}
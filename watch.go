@@ -0,0 +1,182 @@
+/**
+ * Watcher reloads a config file when its content changes.
+ *
+ *      e.g.
+ *          w := NewWatcher("config.conf", 5*time.Second)
+ *          w.OnChange(func(old, cur *Conf) {
+ *              // apply cur
+ *          })
+ *          w.OnError(func(err error) {
+ *              // the last good conf is kept
+ *          })
+ *          if err := w.Start(); err != nil {
+ *              // initial parse failed
+ *          }
+ *          defer w.Stop()
+ *
+ *  The file is polled every interval. A changed file which fails to parse
+ *  is reported to OnError, and the last good conf is kept.
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 13:05:44
+ */
+
+package goconf
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"time"
+)
+
+// Clock is the time source of a Watcher. A fake clock can be set by
+// SetClock, so reload handling can be tested without real sleeps.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+type Watcher struct {
+	path     string
+	interval time.Duration
+	opts     []Option
+	clock    Clock
+	onChange func(old, cur *Conf)
+	onError  func(err error)
+
+	mu      sync.Mutex
+	conf    *Conf
+	content []byte
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWatcher creates a watcher of config file 'path'. The options are used
+// to parse the file, as New.
+func NewWatcher(path string, interval time.Duration, opts ...Option) *Watcher {
+	return &Watcher{
+		path:     path,
+		interval: interval,
+		opts:     opts,
+		clock:    realClock{},
+	}
+}
+
+// SetClock replaces the time source. It must be called before Start.
+func (w *Watcher) SetClock(clock Clock) {
+	w.clock = clock
+}
+
+// OnChange sets the callback invoked after the file is reloaded.
+func (w *Watcher) OnChange(fn func(old, cur *Conf)) {
+	w.onChange = fn
+}
+
+// OnError sets the callback invoked when the file can't be read or parsed.
+func (w *Watcher) OnError(fn func(err error)) {
+	w.onError = fn
+}
+
+// Start parses the file, and polls it in a goroutine until Stop.
+func (w *Watcher) Start() error {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		return fileErr(w.path, err)
+	}
+	conf, err := w.parse(content)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.conf = conf
+	w.content = content
+	w.mu.Unlock()
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go w.loop()
+
+	return nil
+}
+
+// Stop stops polling, and waits for the running callbacks to return.
+func (w *Watcher) Stop() {
+	close(w.stop)
+	<-w.done
+}
+
+// Conf returns the last successfully parsed conf.
+func (w *Watcher) Conf() *Conf {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.conf
+}
+
+// Check polls the file once, and reloads it if the content changed.
+// Callbacks are invoked before Check returns.
+func (w *Watcher) Check() (bool, error) {
+	content, err := os.ReadFile(w.path)
+	if err != nil {
+		err = fileErr(w.path, err)
+		w.reportErr(err)
+		return false, err
+	}
+
+	w.mu.Lock()
+	unchanged := bytes.Equal(content, w.content)
+	w.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	conf, err := w.parse(content)
+	if err != nil {
+		w.reportErr(err)
+		return false, err
+	}
+
+	w.mu.Lock()
+	old := w.conf
+	w.conf = conf
+	w.content = content
+	w.mu.Unlock()
+
+	if w.onChange != nil {
+		w.onChange(old, conf)
+	}
+
+	return true, nil
+}
+
+func (w *Watcher) loop() {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.clock.After(w.interval):
+			w.Check()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+func (w *Watcher) parse(content []byte) (*Conf, error) {
+	conf := New(w.path, w.opts...)
+	if err := conf.parseContent(content); err != nil {
+		return nil, err
+	}
+
+	return conf, nil
+}
+
+func (w *Watcher) reportErr(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
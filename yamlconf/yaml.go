@@ -0,0 +1,83 @@
+/**
+ * Package yamlconf adds YAML config files to goconf. Importing it registers
+ * the extensions '.yaml' and '.yml', then Load and Parse work the same with
+ * YAML files as with goconf files.
+ *
+ *      e.g.
+ *          import _ "github.com/chosen0ne/goconf/yamlconf"
+ *
+ *  Top-level mappings are sections, and the other top-level values are
+ *  global items. Sequences are array items.
+ *
+ *      e.g.
+ *          name: app
+ *          ports: [80, 443]
+ *          db:
+ *            timeout: 1.5
+ *
+ *      is the same as config file:
+ *          > name: app
+ *          > ports: 80 443
+ *          > [db]
+ *          > timeout: 1.5
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 20:15:31
+ */
+
+package yamlconf
+
+import (
+	"github.com/chosen0ne/goconf"
+	"gopkg.in/yaml.v3"
+	"io"
+)
+
+func init() {
+	goconf.RegisterFormat(".yaml", Parse)
+	goconf.RegisterFormat(".yml", Parse)
+}
+
+// Parse is the goconf.FormatParser of YAML files. Items keep the order of
+// the file.
+func Parse(conf *goconf.Conf, r io.Reader, path string) error {
+	var doc yaml.Node
+	var m map[string]interface{}
+	err := yaml.NewDecoder(r).Decode(&doc)
+	if err == nil {
+		err = doc.Decode(&m)
+	}
+	if err != nil && err != io.EOF {
+		return &goconf.ParseError{
+			Code: goconf.E_FORMAT,
+			File: path,
+			Msg:  "invalid YAML: " + err.Error(),
+			Err:  err,
+		}
+	}
+
+	return conf.MergeMapInOrder(m, keys(&doc))
+}
+
+// keys returns the paths of the keys of the top two levels of doc, in order
+// of the file.
+func keys(doc *yaml.Node) []string {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var paths []string
+	root := doc.Content[0].Content
+	for i := 0; i+1 < len(root); i += 2 {
+		key, val := root[i].Value, root[i+1]
+		paths = append(paths, key)
+		if val.Kind != yaml.MappingNode {
+			continue
+		}
+		for j := 0; j+1 < len(val.Content); j += 2 {
+			paths = append(paths, key+"."+val.Content[j].Value)
+		}
+	}
+
+	return paths
+}
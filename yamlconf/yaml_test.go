@@ -0,0 +1,37 @@
+/**
+ * Unit test cases for YAML config files
+ *
+ * @author  chosen0ne(louzhenlin86@126.com)
+ * @date    2026/10/16 20:31:07
+ */
+
+package yamlconf
+
+import (
+	"github.com/chosen0ne/goconf"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	content := "name: app\nports:\n  - 80\n  - 443\ndebug: true\ndb:\n  timeout: 1.5\n"
+	os.WriteFile(path, []byte(content), 0644)
+
+	configObj := struct {
+		Name  string
+		Ports []int64
+		Debug bool
+		Db    struct {
+			Timeout float64
+		}
+	}{}
+	if err := goconf.Load(&configObj, path); err != nil {
+		t.Fatalf("failed to load conf, err: %s", err)
+	}
+
+	if configObj.Name != "app" || len(configObj.Ports) != 2 || !configObj.Debug || configObj.Db.Timeout != 1.5 {
+		t.Errorf("not expected output, obj: %v", configObj)
+	}
+}